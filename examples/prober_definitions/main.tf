@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    probe = {
+      source = "shakefu/probe"
+    }
+  }
+}
+
+provider "probe" {
+  prober_definitions = "${path.module}/probers.yaml"
+}
+
+data "probe" "deploy_role" {
+  type = "aws_iam_role"
+  id   = "deploy"
+}
+
+data "probe" "events" {
+  type = "AWS::Kinesis::Stream"
+  id   = "events"
+}
+
+output "deploy_role_arn" {
+  value = data.probe.deploy_role.arn
+}
+
+output "events_stream_exists" {
+  value = data.probe.events.exists
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// checkOutput is the JSON document written by the check command.
+type checkOutput struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id"`
+	Exists     bool              `json:"exists"`
+	Arn        string            `json:"arn,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	State      string            `json:"lifecycle_state,omitempty"`
+	Properties map[string]any    `json:"properties,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
+
+// runCheck implements the check command.
+func runCheck(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var aws awsFlags
+	var resourceType, identifier, output, existsIf string
+
+	fs.StringVar(&resourceType, "type", "", "resource type (e.g., aws_dynamodb_table or AWS::DynamoDB::Table)")
+	fs.StringVar(&identifier, "id", "", "resource identifier (table name, bucket name, etc.)")
+	fs.StringVar(&output, "output", "text", "output format: text or json")
+	fs.StringVar(&existsIf, "exists-if", "", "comma-separated lifecycle states in which the resource counts as existing, as the data source's exists_if (default every state)")
+	aws.register(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return ExitError
+	}
+
+	if resourceType == "" || identifier == "" {
+		fmt.Fprintln(stderr, "check: -type and -id are required")
+		fs.Usage()
+		return ExitError
+	}
+	if output != "text" && output != "json" {
+		fmt.Fprintf(stderr, "check: invalid -output %q (expected text or json)\n", output)
+		return ExitError
+	}
+	var states []string
+	if existsIf != "" {
+		states = strings.Split(existsIf, ",")
+		if err := checkLifecycleStates(states); err != nil {
+			fmt.Fprintf(stderr, "check: invalid -exists-if: %v\n", err)
+			return ExitError
+		}
+	}
+
+	registry, err := aws.registry(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "check: %v\n", err)
+		return ExitError
+	}
+
+	prober, err := registry.GetProber(resourceType)
+	if err != nil {
+		hint := ""
+		if suggestion := registry.Catalog().Suggest(resourceType); suggestion != "" {
+			hint = fmt.Sprintf(" Did you mean %q?", suggestion)
+		}
+		fmt.Fprintf(stderr, "check: resource type %q is not supported.%s Supported types: %v\n", resourceType, hint, registry.SupportedTypes())
+		return ExitError
+	}
+	if iv, ok := prober.(probe.IdentifierValidator); ok {
+		if err := iv.ValidateIdentifier(identifier); err != nil {
+			fmt.Fprintf(stderr, "check: %q is not a valid %s identifier: %v\n", identifier, resourceType, err)
+			return ExitError
+		}
+	}
+	if il, ok := prober.(probe.IdentifierLinter); ok {
+		if err := il.LintIdentifier(identifier); err != nil {
+			fmt.Fprintf(stderr, "check: warning: %q breaks the current %s naming rules: %v\n", identifier, resourceType, err)
+		}
+	}
+
+	result, err := prober.Probe(ctx, identifier)
+	if err != nil {
+		fmt.Fprintf(stderr, "check: probe failed: %v\n", err)
+		return ExitError
+	}
+
+	// The state is reported even when -exists-if treats the resource as
+	// missing
+	state := result.Lifecycle()
+	result = result.ExistsIn(states)
+
+	out := checkOutput{
+		Type:   resourceType,
+		ID:     identifier,
+		Exists: result.Exists,
+		Status: result.Status,
+		State:  state,
+	}
+	if result.Exists {
+		out.Arn = result.Arn
+		out.Properties = result.Properties
+		out.Tags = result.Tags
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(stderr, "check: failed to encode output: %v\n", err)
+			return ExitError
+		}
+	} else {
+		fmt.Fprintf(stdout, "type:   %s\n", out.Type)
+		fmt.Fprintf(stdout, "id:     %s\n", out.ID)
+		fmt.Fprintf(stdout, "exists: %t\n", out.Exists)
+		if out.Arn != "" {
+			fmt.Fprintf(stdout, "arn:    %s\n", out.Arn)
+		}
+		if out.Status != "" {
+			fmt.Fprintf(stdout, "status: %s\n", out.Status)
+		}
+		if out.State != "" {
+			fmt.Fprintf(stdout, "state:  %s\n", out.State)
+		}
+	}
+
+	if !result.Exists {
+		return ExitMissing
+	}
+	return ExitOK
+}
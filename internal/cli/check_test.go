@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+)
+
+// newDynamoDBServer returns a fake AWS server with a single existing table.
+func newDynamoDBServer(t *testing.T, tableName string) *fakeaws.Server {
+	t.Helper()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	server := fakeaws.New(t)
+	server.PutTable(fakeaws.Table{
+		Name: tableName,
+		Arn:  "arn:aws:dynamodb:us-east-1:000000000000:table/" + tableName,
+		Tags: map[string]string{"Environment": "test"},
+	})
+
+	return server
+}
+
+func TestCheck_Exists(t *testing.T) {
+	server := newDynamoDBServer(t, "my-table")
+	var stdout, stderr bytes.Buffer
+
+	code := Run(context.Background(), []string{
+		"check", "--type", "aws_dynamodb_table", "--id", "my-table",
+		"--region", "us-east-1", "--endpoint", server.URL, "--output", "json",
+	}, &stdout, &stderr)
+
+	if code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitOK, code, stderr.String())
+	}
+
+	var out checkOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed to decode output: %v\n%s", err, stdout.String())
+	}
+
+	if !out.Exists {
+		t.Error("expected exists=true")
+	}
+	if out.Arn != "arn:aws:dynamodb:us-east-1:000000000000:table/my-table" {
+		t.Errorf("unexpected arn %q", out.Arn)
+	}
+	if out.Tags["Environment"] != "test" {
+		t.Errorf("expected Environment tag, got %v", out.Tags)
+	}
+}
+
+func TestCheck_Missing(t *testing.T) {
+	server := newDynamoDBServer(t, "my-table")
+	var stdout, stderr bytes.Buffer
+
+	code := Run(context.Background(), []string{
+		"check", "-type", "AWS::DynamoDB::Table", "-id", "other-table", "-endpoint", server.URL,
+	}, &stdout, &stderr)
+
+	if code != ExitMissing {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitMissing, code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "exists: false") {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+}
+
+func TestCheck_ExistsIf(t *testing.T) {
+	server := newDynamoDBServer(t, "my-table")
+	server.PutTable(fakeaws.Table{Name: "old-table", Status: "DELETING"})
+
+	run := func(args ...string) (int, checkOutput) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		code := Run(context.Background(), append([]string{
+			"check", "-type", "aws_dynamodb_table", "-endpoint", server.URL, "-output", "json",
+		}, args...), &stdout, &stderr)
+
+		var out checkOutput
+		if code != ExitError {
+			if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+				t.Fatalf("failed to decode output: %v\n%s", err, stdout.String())
+			}
+		}
+		return code, out
+	}
+
+	if code, out := run("-id", "old-table"); code != ExitOK || out.State != "deleting" {
+		t.Errorf("expected raw existence by default, got exit code %d and %+v", code, out)
+	}
+
+	code, out := run("-id", "old-table", "-exists-if", "active,updating")
+	if code != ExitMissing || out.Exists {
+		t.Errorf("expected a deleting table to be missing, got exit code %d and %+v", code, out)
+	}
+	if out.Status != "DELETING" || out.State != "deleting" {
+		t.Errorf("expected the status and state to be reported, got %+v", out)
+	}
+
+	if code, _ := run("-id", "my-table", "-exists-if", "active,updating"); code != ExitOK {
+		t.Errorf("expected an active table to exist, got exit code %d", code)
+	}
+	if code, _ := run("-id", "my-table", "-exists-if", "gone"); code != ExitError {
+		t.Errorf("expected an invalid state to fail, got exit code %d", code)
+	}
+}
+
+func TestCheck_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "missing id",
+			args: []string{"check", "-type", "aws_s3_bucket"},
+			want: "-type and -id are required",
+		},
+		{
+			name: "invalid output",
+			args: []string{"check", "-type", "aws_s3_bucket", "-id", "b", "-output", "yaml"},
+			want: `invalid -output "yaml"`,
+		},
+		{
+			name: "unsupported type",
+			args: []string{"check", "-type", "aws_unknown_thing", "-id", "x", "-localstack", "false"},
+			want: "is not supported",
+		},
+		{
+			name: "type suggestion",
+			args: []string{"check", "-type", "aws_dynamo_table", "-id", "x", "-localstack", "false"},
+			want: `Did you mean "aws_dynamodb_table"?`,
+		},
+		{
+			name: "invalid identifier",
+			args: []string{"check", "-type", "aws_s3_bucket", "-id", "assets/logs", "-localstack", "false"},
+			want: "bucket names may only contain letters, digits, periods",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := Run(context.Background(), tt.args, &stdout, &stderr)
+			if code != ExitError {
+				t.Errorf("expected exit code %d, got %d", ExitError, code)
+			}
+			if !strings.Contains(stderr.String(), tt.want) {
+				t.Errorf("expected stderr to contain %q, got %q", tt.want, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cli implements the standalone command-line mode of the provider
+// binary, which runs probes outside of Terraform.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shakefu/terraform-provider-probe/internal/provider"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// Exit codes returned by Run.
+const (
+	// ExitOK means the command succeeded (and, for check, that the resource exists).
+	ExitOK = 0
+
+	// ExitMissing means the probed resource does not exist.
+	ExitMissing = 1
+
+	// ExitError means the command failed or was invoked incorrectly.
+	ExitError = 2
+)
+
+// command is a CLI subcommand.
+type command struct {
+	synopsis string
+	run      func(ctx context.Context, args []string, stdout, stderr io.Writer) int
+}
+
+// commands maps subcommand names to their implementations.
+var commands = map[string]command{
+	"check": {
+		synopsis: "Probe a single resource and report whether it exists",
+		run:      runCheck,
+	},
+	"import": {
+		synopsis: "Generate import blocks for probed resources that exist",
+		run:      runImport,
+	},
+	"scan": {
+		synopsis: "Evaluate the probe data sources in a Terraform directory",
+		run:      runScan,
+	},
+}
+
+// IsCommand reports whether name is a CLI subcommand. main uses this to decide
+// between serving the plugin protocol and running in CLI mode.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok || name == "help"
+}
+
+// Run executes the subcommand named by args[0] and returns the process exit code.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" {
+		usage(stderr)
+		return ExitError
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		usage(stderr)
+		return ExitError
+	}
+
+	return cmd.run(ctx, args[1:], stdout, stderr)
+}
+
+// usage writes the list of available subcommands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: terraform-provider-probe <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].synopsis)
+	}
+}
+
+// checkLifecycleStates reports the first of states that isn't a lifecycle
+// state, as the probe data source's exists_if does. A nil list counts every
+// state, but an empty one is rejected.
+func checkLifecycleStates(states []string) error {
+	if states != nil && len(states) == 0 {
+		return errors.New("exists_if must list at least one lifecycle state")
+	}
+	for _, state := range states {
+		if !slices.Contains(probe.LifecycleStates(), state) {
+			return fmt.Errorf("%q is not a lifecycle state (expected one of %s)", state, strings.Join(probe.LifecycleStates(), ", "))
+		}
+	}
+	return nil
+}
+
+// awsFlags holds the flags shared by every command that talks to AWS. They
+// mirror the provider block attributes.
+type awsFlags struct {
+	region            string
+	endpoint          string
+	emulator          string
+	localstack        string
+	proberDefinitions string
+}
+
+// register adds the AWS flags to a flag set.
+func (f *awsFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.region, "region", "", "AWS region (defaults to AWS_REGION, AWS_DEFAULT_REGION, then us-east-1)")
+	fs.StringVar(&f.endpoint, "endpoint", "", "override the AWS endpoint URL (implies -localstack=true)")
+	fs.StringVar(&f.emulator, "emulator", "", "local emulator to target ("+strings.Join(provider.EmulatorNames(), ", ")+")")
+	fs.StringVar(&f.localstack, "localstack", "", "explicitly enable or disable LocalStack (auto-detected if unset)")
+	fs.StringVar(&f.proberDefinitions, "prober-definitions", "", "path to a file or directory of declarative prober definitions")
+}
+
+// settings converts the flags into provider.AWSSettings.
+func (f *awsFlags) settings() (provider.AWSSettings, error) {
+	settings := provider.AWSSettings{
+		Region:   f.region,
+		Endpoint: f.endpoint,
+		Emulator: f.emulator,
+	}
+
+	if f.localstack != "" {
+		enabled, err := strconv.ParseBool(f.localstack)
+		if err != nil {
+			return settings, fmt.Errorf("invalid -localstack value %q: %w", f.localstack, err)
+		}
+		settings.LocalStack = &enabled
+	}
+
+	return settings, nil
+}
+
+// registry resolves the AWS configuration from the flags and returns a
+// ProberRegistry that uses it.
+func (f *awsFlags) registry(ctx context.Context) (*probe.ProberRegistry, error) {
+	settings, err := f.settings()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := provider.LoadAWSConfig(ctx, settings)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS configuration: %w", err)
+	}
+
+	catalog := probe.DefaultCatalog()
+
+	if f.proberDefinitions != "" {
+		defs, err := provider.LoadProberDefinitions(f.proberDefinitions)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prober definitions: %w", err)
+		}
+		catalog = catalog.Clone()
+		provider.RegisterDefinitions(catalog, defs)
+	}
+
+	return probe.NewProberRegistryWithCatalog(cfg, catalog), nil
+}
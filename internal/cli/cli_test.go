@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestIsCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "check", expected: true},
+		{name: "help", expected: true},
+		{name: "-debug", expected: false},
+		{name: "serve", expected: false},
+		{name: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCommand(tt.name); got != tt.expected {
+				t.Errorf("IsCommand(%q) = %v, want %v", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := Run(context.Background(), []string{"help"}, &stdout, &stderr)
+	if code != ExitError {
+		t.Errorf("expected exit code %d, got %d", ExitError, code)
+	}
+	if !strings.Contains(stderr.String(), "check") {
+		t.Errorf("expected usage to list the check command, got %q", stderr.String())
+	}
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := Run(context.Background(), []string{"bogus"}, &stdout, &stderr)
+	if code != ExitError {
+		t.Errorf("expected exit code %d, got %d", ExitError, code)
+	}
+	if !strings.Contains(stderr.String(), `unknown command "bogus"`) {
+		t.Errorf("unexpected stderr: %q", stderr.String())
+	}
+}
+
+func TestCheckLifecycleStates(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  []string
+		wantErr string
+	}{
+		{name: "unset", states: nil},
+		{name: "valid", states: []string{"active", "updating"}},
+		{name: "empty", states: []string{}, wantErr: "at least one lifecycle state"},
+		{name: "invalid", states: []string{"active", "gone"}, wantErr: `"gone" is not a lifecycle state`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkLifecycleStates(tt.states)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAWSFlags_Settings(t *testing.T) {
+	t.Run("localstack unset", func(t *testing.T) {
+		f := awsFlags{region: "us-west-2"}
+		settings, err := f.settings()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.LocalStack != nil {
+			t.Error("expected LocalStack to be nil for auto-detection")
+		}
+		if settings.Region != "us-west-2" {
+			t.Errorf("expected Region=us-west-2, got %q", settings.Region)
+		}
+	})
+
+	t.Run("localstack false", func(t *testing.T) {
+		f := awsFlags{localstack: "false"}
+		settings, err := f.settings()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.LocalStack == nil || *settings.LocalStack {
+			t.Error("expected LocalStack to be explicitly false")
+		}
+	})
+
+	t.Run("emulator", func(t *testing.T) {
+		f := awsFlags{emulator: "minio"}
+		settings, err := f.settings()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.Emulator != "minio" {
+			t.Errorf("expected Emulator=minio, got %q", settings.Emulator)
+		}
+	})
+
+	t.Run("localstack invalid", func(t *testing.T) {
+		f := awsFlags{localstack: "maybe"}
+		if _, err := f.settings(); err == nil {
+			t.Error("expected error for invalid -localstack value")
+		}
+	})
+}
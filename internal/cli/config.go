@@ -0,0 +1,264 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// probeBlock is a `data "probe"` block found in a Terraform configuration.
+type probeBlock struct {
+	// Name is the block's local name (the second label).
+	Name string
+
+	// Type and ID are the resolved type and id arguments. They are empty when
+	// Unresolved is set.
+	Type string
+	ID   string
+
+	// ExistsIf is the resolved exists_if argument, or nil if it isn't set.
+	ExistsIf []string
+
+	// Unresolved explains why type, id or exists_if could not be evaluated
+	// statically, e.g. because it references a resource attribute.
+	Unresolved string
+}
+
+// Address returns the Terraform address of the block.
+func (b probeBlock) Address() string {
+	return "data.probe." + b.Name
+}
+
+// rootSchema selects the top-level blocks the CLI understands. Everything
+// else in the configuration is ignored.
+var rootSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "data", LabelNames: []string{"type", "name"}},
+		{Type: "variable", LabelNames: []string{"name"}},
+	},
+}
+
+// probeSchema selects the arguments of a probe data source block.
+var probeSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "type", Required: true},
+		{Name: "id", Required: true},
+		{Name: "exists_if"},
+	},
+}
+
+// variableSchema selects the default of a variable block.
+var variableSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "default"},
+	},
+}
+
+// parseFile parses an HCL file, or its JSON form if the name ends in .json,
+// as Terraform does for .tf.json and .tfvars.json files.
+func parseFile(parser *hclparse.Parser, path string) (*hcl.File, hcl.Diagnostics) {
+	if strings.HasSuffix(path, ".json") {
+		return parser.ParseJSONFile(path)
+	}
+	return parser.ParseHCLFile(path)
+}
+
+// loadProbeBlocks parses the .tf and .tf.json files in dir and returns its
+// probe data sources, sorted by address. Variables are resolved from their
+// defaults, overridden by the given tfvars files in order.
+func loadProbeBlocks(dir string, varFiles []string) ([]probeBlock, error) {
+	parser := hclparse.NewParser()
+
+	var paths []string
+	for _, pattern := range []string{"*.tf", "*.tf.json"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .tf or .tf.json files found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	var diags hcl.Diagnostics
+	var dataBlocks []*hcl.Block
+	variables := make(map[string]cty.Value)
+
+	for _, path := range paths {
+		file, fileDiags := parseFile(parser, path)
+		diags = append(diags, fileDiags...)
+		if fileDiags.HasErrors() {
+			continue
+		}
+
+		content, _, contentDiags := file.Body.PartialContent(rootSchema)
+		diags = append(diags, contentDiags...)
+
+		for _, block := range content.Blocks {
+			switch block.Type {
+			case "data":
+				if block.Labels[0] == "probe" {
+					dataBlocks = append(dataBlocks, block)
+				}
+			case "variable":
+				attrs, _, varDiags := block.Body.PartialContent(variableSchema)
+				diags = append(diags, varDiags...)
+				if def, ok := attrs.Attributes["default"]; ok {
+					val, valDiags := def.Expr.Value(nil)
+					diags = append(diags, valDiags...)
+					variables[block.Labels[0]] = val
+				} else {
+					variables[block.Labels[0]] = cty.DynamicVal
+				}
+			}
+		}
+	}
+
+	for _, path := range varFiles {
+		file, fileDiags := parseFile(parser, path)
+		diags = append(diags, fileDiags...)
+		if fileDiags.HasErrors() {
+			continue
+		}
+
+		attrs, attrDiags := file.Body.JustAttributes()
+		diags = append(diags, attrDiags...)
+		for name, attr := range attrs {
+			val, valDiags := attr.Expr.Value(nil)
+			diags = append(diags, valDiags...)
+			variables[name] = val
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, errors.New(diags.Error())
+	}
+
+	evalCtx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(variables),
+		},
+	}
+
+	blocks := make([]probeBlock, 0, len(dataBlocks))
+	for _, block := range dataBlocks {
+		blocks = append(blocks, resolveProbeBlock(block, evalCtx))
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Name < blocks[j].Name
+	})
+
+	return blocks, nil
+}
+
+// resolveProbeBlock evaluates the type, id and exists_if arguments of a
+// probe block.
+func resolveProbeBlock(block *hcl.Block, evalCtx *hcl.EvalContext) probeBlock {
+	result := probeBlock{Name: block.Labels[1]}
+
+	content, _, diags := block.Body.PartialContent(probeSchema)
+	if diags.HasErrors() {
+		result.Unresolved = diags.Error()
+		return result
+	}
+
+	var unresolved []string
+	for _, name := range []string{"type", "id"} {
+		value, reason := evalString(content.Attributes[name].Expr, evalCtx)
+		if reason != "" {
+			unresolved = append(unresolved, name+": "+reason)
+			continue
+		}
+		if name == "type" {
+			result.Type = value
+		} else {
+			result.ID = value
+		}
+	}
+
+	if attr, ok := content.Attributes["exists_if"]; ok {
+		states, reason := evalStrings(attr.Expr, evalCtx)
+		if reason != "" {
+			unresolved = append(unresolved, "exists_if: "+reason)
+		}
+		result.ExistsIf = states
+	}
+
+	if len(unresolved) > 0 {
+		result.Type, result.ID, result.ExistsIf = "", "", nil
+		result.Unresolved = strings.Join(unresolved, "; ")
+	}
+
+	return result
+}
+
+// evalString evaluates expr to a known string. It returns a reason instead of
+// a value when the expression can't be resolved without running Terraform.
+func evalString(expr hcl.Expression, evalCtx *hcl.EvalContext) (string, string) {
+	for _, traversal := range expr.Variables() {
+		if root := traversal.RootName(); root != "var" {
+			return "", fmt.Sprintf("references %s", root)
+		}
+	}
+
+	val, diags := expr.Value(evalCtx)
+	if diags.HasErrors() {
+		return "", diags.Error()
+	}
+	if !val.IsWhollyKnown() {
+		return "", "depends on a variable with no value"
+	}
+	val, err := convert.Convert(val, cty.String)
+	if err != nil || val.IsNull() {
+		return "", "not a string"
+	}
+
+	return val.AsString(), ""
+}
+
+// evalStrings evaluates expr to a known list of strings, in the same way as
+// evalString. A null list resolves to nil.
+func evalStrings(expr hcl.Expression, evalCtx *hcl.EvalContext) ([]string, string) {
+	for _, traversal := range expr.Variables() {
+		if root := traversal.RootName(); root != "var" {
+			return nil, fmt.Sprintf("references %s", root)
+		}
+	}
+
+	val, diags := expr.Value(evalCtx)
+	if diags.HasErrors() {
+		return nil, diags.Error()
+	}
+	if !val.IsWhollyKnown() {
+		return nil, "depends on a variable with no value"
+	}
+	val, err := convert.Convert(val, cty.List(cty.String))
+	if err != nil {
+		return nil, "not a list of strings"
+	}
+	if val.IsNull() {
+		return nil, ""
+	}
+
+	states := make([]string, 0, val.LengthInt())
+	for _, element := range val.AsValueSlice() {
+		if element.IsNull() {
+			return nil, "contains null"
+		}
+		states = append(states, element.AsString())
+	}
+	return states, ""
+}
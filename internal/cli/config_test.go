@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates the given files in a temporary directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+const testScanConfig = `
+variable "prefix" {
+  default = "dev"
+}
+
+variable "bucket" {}
+
+data "probe" "table" {
+  type = "aws_dynamodb_table"
+  id   = "${var.prefix}-contacts"
+}
+
+data "probe" "bucket" {
+  type = "aws_s3_bucket"
+  id   = var.bucket
+}
+
+data "probe" "computed" {
+  type = "aws_dynamodb_table"
+  id   = aws_dynamodb_table.other.name
+}
+
+data "aws_caller_identity" "current" {}
+
+resource "aws_dynamodb_table" "other" {
+  name = "other"
+}
+`
+
+func TestLoadProbeBlocks(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.tf":          testScanConfig,
+		"prod.tfvars":      "prefix = \"prod\"\nbucket = \"prod-assets\"\n",
+		"prod.tfvars.json": `{"prefix": "json", "bucket": "json-assets"}`,
+		"README.md":        "not terraform",
+		"ignored.tfvar":    "prefix = 1",
+	})
+
+	t.Run("defaults only", func(t *testing.T) {
+		blocks, err := loadProbeBlocks(dir, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(blocks) != 3 {
+			t.Fatalf("expected 3 probe blocks, got %d: %+v", len(blocks), blocks)
+		}
+
+		// Sorted by name: bucket, computed, table
+		if blocks[0].Name != "bucket" || !strings.Contains(blocks[0].Unresolved, "no value") {
+			t.Errorf("expected bucket to be unresolved without tfvars, got %+v", blocks[0])
+		}
+		if blocks[1].Name != "computed" || !strings.Contains(blocks[1].Unresolved, "references aws_dynamodb_table") {
+			t.Errorf("expected computed to be unresolved, got %+v", blocks[1])
+		}
+		if blocks[2].Address() != "data.probe.table" || blocks[2].ID != "dev-contacts" {
+			t.Errorf("expected table id from variable default, got %+v", blocks[2])
+		}
+	})
+
+	t.Run("tfvars override defaults", func(t *testing.T) {
+		blocks, err := loadProbeBlocks(dir, []string{filepath.Join(dir, "prod.tfvars")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if blocks[0].ID != "prod-assets" || blocks[0].Type != "aws_s3_bucket" {
+			t.Errorf("expected bucket id from tfvars, got %+v", blocks[0])
+		}
+		if blocks[2].ID != "prod-contacts" {
+			t.Errorf("expected table id from tfvars, got %+v", blocks[2])
+		}
+	})
+
+	t.Run("tfvars.json override defaults", func(t *testing.T) {
+		blocks, err := loadProbeBlocks(dir, []string{filepath.Join(dir, "prod.tfvars.json")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if blocks[0].ID != "json-assets" {
+			t.Errorf("expected bucket id from tfvars.json, got %+v", blocks[0])
+		}
+		if blocks[2].ID != "json-contacts" {
+			t.Errorf("expected table id from tfvars.json, got %+v", blocks[2])
+		}
+	})
+}
+
+func TestLoadProbeBlocks_JSON(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.tf": `variable "prefix" { default = "dev" }`,
+		"probes.tf.json": `{
+  "data": {
+    "probe": {
+      "table": {"type": "aws_dynamodb_table", "id": "${var.prefix}-contacts"}
+    }
+  }
+}`,
+	})
+
+	blocks, err := loadProbeBlocks(dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 probe block, got %d: %+v", len(blocks), blocks)
+	}
+	if blocks[0].Address() != "data.probe.table" || blocks[0].Type != "aws_dynamodb_table" || blocks[0].ID != "dev-contacts" {
+		t.Errorf("expected table from .tf.json, got %+v", blocks[0])
+	}
+}
+
+func TestLoadProbeBlocks_Errors(t *testing.T) {
+	t.Run("no configuration", func(t *testing.T) {
+		if _, err := loadProbeBlocks(t.TempDir(), nil); err == nil {
+			t.Error("expected error for directory without .tf files")
+		}
+	})
+
+	t.Run("invalid HCL", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{"main.tf": `data "probe" "x" {`})
+		if _, err := loadProbeBlocks(dir, nil); err == nil {
+			t.Error("expected parse error")
+		}
+	})
+
+	t.Run("missing required argument", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{"main.tf": `data "probe" "x" { type = "aws_s3_bucket" }`})
+		blocks, err := loadProbeBlocks(dir, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if blocks[0].Unresolved == "" {
+			t.Error("expected block without id to be unresolved")
+		}
+	})
+}
@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// runImport implements the import command. It probes the probe data sources
+// in a directory and writes an import block for every resource that exists.
+func runImport(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var aws awsFlags
+	var varFiles stringsFlag
+
+	fs.Var(&varFiles, "var-file", "tfvars file used to resolve variables (repeatable)")
+	aws.register(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return ExitError
+	}
+
+	dir := "."
+	if fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "import: expected at most one directory argument")
+		return ExitError
+	} else if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+
+	blocks, err := loadProbeBlocks(dir, varFiles)
+	if err != nil {
+		fmt.Fprintf(stderr, "import: %v\n", err)
+		return ExitError
+	}
+
+	registry, err := aws.registry(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "import: %v\n", err)
+		return ExitError
+	}
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	code := ExitOK
+
+	for _, block := range blocks {
+		if block.Unresolved != "" {
+			fmt.Fprintf(stderr, "import: skipping %s: %s\n", block.Address(), block.Unresolved)
+			continue
+		}
+
+		prober, err := registry.GetProber(block.Type)
+		if err == nil {
+			err = checkLifecycleStates(block.ExistsIf)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "import: skipping %s: %v\n", block.Address(), err)
+			code = ExitError
+			continue
+		}
+
+		result, err := prober.Probe(ctx, block.ID)
+		if err != nil {
+			fmt.Fprintf(stderr, "import: skipping %s: probe failed: %v\n", block.Address(), err)
+			code = ExitError
+			continue
+		}
+		if !result.ExistsIn(block.ExistsIf).Exists {
+			continue
+		}
+		if result.TerraformType == "" || result.ImportID == "" {
+			fmt.Fprintf(stderr, "import: skipping %s: %s does not report an import ID\n", block.Address(), block.Type)
+			continue
+		}
+
+		if len(body.Blocks()) > 0 {
+			body.AppendNewline()
+		}
+		writeImportBlock(body, result.TerraformType, block.Name, result.ImportID)
+	}
+
+	if _, err := stdout.Write(file.Bytes()); err != nil {
+		return ExitError
+	}
+
+	return code
+}
+
+// writeImportBlock appends an import block that adopts id into the
+// resource address terraformType.name.
+func writeImportBlock(body *hclwrite.Body, terraformType, name, id string) {
+	block := body.AppendNewBlock("import", nil).Body()
+	block.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: terraformType},
+		hcl.TraverseAttr{Name: name},
+	})
+	block.SetAttributeValue("id", cty.StringVal(id))
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	server := newDynamoDBServer(t, "dev-contacts")
+	dir := writeFiles(t, map[string]string{
+		"main.tf": `
+variable "prefix" {
+  default = "dev"
+}
+
+data "probe" "contacts" {
+  type = "aws_dynamodb_table"
+  id   = "${var.prefix}-contacts"
+}
+
+data "probe" "orders" {
+  type = "aws_dynamodb_table"
+  id   = "${var.prefix}-orders"
+}
+
+data "probe" "computed" {
+  type = "aws_dynamodb_table"
+  id   = local.name
+}
+`,
+	})
+	var stdout, stderr bytes.Buffer
+
+	code := Run(context.Background(), []string{"import", "-endpoint", server.URL, dir}, &stdout, &stderr)
+	if code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitOK, code, stderr.String())
+	}
+
+	expected := `import {
+  to = aws_dynamodb_table.contacts
+  id = "dev-contacts"
+}
+`
+	if stdout.String() != expected {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", stdout.String(), expected)
+	}
+	if !strings.Contains(stderr.String(), "skipping data.probe.computed") {
+		t.Errorf("expected unresolved block to be reported, got %q", stderr.String())
+	}
+}
+
+func TestImport_MultipleBlocks(t *testing.T) {
+	var out bytes.Buffer
+	server := newDynamoDBServer(t, "a")
+	dir := writeFiles(t, map[string]string{
+		"main.tf": `
+data "probe" "a" {
+  type = "aws_dynamodb_table"
+  id   = "a"
+}
+
+data "probe" "b" {
+  type = "AWS::DynamoDB::Table"
+  id   = "a"
+}
+`,
+	})
+
+	code := Run(context.Background(), []string{"import", "-endpoint", server.URL, dir}, &out, &bytes.Buffer{})
+	if code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d", ExitOK, code)
+	}
+
+	if got := strings.Count(out.String(), "import {"); got != 2 {
+		t.Errorf("expected 2 import blocks, got %d:\n%s", got, out.String())
+	}
+	if !strings.Contains(out.String(), "}\n\nimport {") {
+		t.Errorf("expected blocks to be separated by a blank line:\n%s", out.String())
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// Scan statuses reported per probe block.
+const (
+	statusExists     = "exists"
+	statusMissing    = "missing"
+	statusForbidden  = "forbidden"
+	statusUnresolved = "unresolved"
+	statusError      = "error"
+)
+
+// scanResult is one row of the scan report.
+type scanResult struct {
+	Address string `json:"address"`
+	Type    string `json:"type,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Status  string `json:"status"`
+	Arn     string `json:"arn,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// stringsFlag is a repeatable string flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runScan implements the scan command.
+func runScan(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var aws awsFlags
+	var varFiles stringsFlag
+	var output string
+
+	fs.Var(&varFiles, "var-file", "tfvars file used to resolve variables (repeatable)")
+	fs.StringVar(&output, "output", "text", "output format: text or json")
+	aws.register(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return ExitError
+	}
+	if output != "text" && output != "json" {
+		fmt.Fprintf(stderr, "scan: invalid -output %q (expected text or json)\n", output)
+		return ExitError
+	}
+
+	dir := "."
+	if fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "scan: expected at most one directory argument")
+		return ExitError
+	} else if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+
+	blocks, err := loadProbeBlocks(dir, varFiles)
+	if err != nil {
+		fmt.Fprintf(stderr, "scan: %v\n", err)
+		return ExitError
+	}
+
+	registry, err := aws.registry(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "scan: %v\n", err)
+		return ExitError
+	}
+
+	results := make([]scanResult, 0, len(blocks))
+	code := ExitOK
+	for _, block := range blocks {
+		result := scanBlock(ctx, registry, block)
+		if result.Status == statusError {
+			code = ExitError
+		}
+		results = append(results, result)
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fmt.Fprintf(stderr, "scan: failed to encode output: %v\n", err)
+			return ExitError
+		}
+		return code
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tTYPE\tID\tSTATUS\tDETAIL")
+	for _, r := range results {
+		detail := r.Detail
+		if detail == "" {
+			detail = r.Arn
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Address, dash(r.Type), dash(r.ID), r.Status, detail)
+	}
+	if err := tw.Flush(); err != nil {
+		return ExitError
+	}
+
+	return code
+}
+
+// scanBlock probes a single block and classifies the outcome.
+func scanBlock(ctx context.Context, registry *probe.ProberRegistry, block probeBlock) scanResult {
+	result := scanResult{
+		Address: block.Address(),
+		Type:    block.Type,
+		ID:      block.ID,
+	}
+
+	if block.Unresolved != "" {
+		result.Status = statusUnresolved
+		result.Detail = block.Unresolved
+		return result
+	}
+
+	prober, err := registry.GetProber(block.Type)
+	if err == nil {
+		err = checkLifecycleStates(block.ExistsIf)
+	}
+	if err != nil {
+		result.Status = statusError
+		result.Detail = err.Error()
+		return result
+	}
+
+	probed, err := prober.Probe(ctx, block.ID)
+	switch {
+	case err != nil && isForbidden(err):
+		result.Status = statusForbidden
+		result.Detail = err.Error()
+	case err != nil:
+		result.Status = statusError
+		result.Detail = err.Error()
+	case probed.ExistsIn(block.ExistsIf).Exists:
+		result.Status = statusExists
+		result.Arn = probed.Arn
+	default:
+		result.Status = statusMissing
+	}
+
+	return result
+}
+
+// isForbidden reports whether err is an authorization failure rather than
+// an outage or misconfiguration.
+func isForbidden(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "AccessDenied", "AccessDeniedException", "Forbidden", "UnauthorizedOperation":
+			return true
+		}
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode() == http.StatusForbidden
+	}
+
+	return false
+}
+
+// dash substitutes "-" for empty table cells.
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
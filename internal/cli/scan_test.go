@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+)
+
+func TestScan(t *testing.T) {
+	server := newDynamoDBServer(t, "dev-contacts")
+	dir := writeFiles(t, map[string]string{
+		"main.tf": `
+variable "prefix" {
+  default = "dev"
+}
+
+data "probe" "contacts" {
+  type = "aws_dynamodb_table"
+  id   = "${var.prefix}-contacts"
+}
+
+data "probe" "orders" {
+  type = "AWS::DynamoDB::Table"
+  id   = "${var.prefix}-orders"
+}
+
+data "probe" "computed" {
+  type = "aws_dynamodb_table"
+  id   = local.name
+}
+`,
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := Run(context.Background(), []string{"scan", "-endpoint", server.URL, "-output", "json", dir}, &stdout, &stderr)
+		if code != ExitOK {
+			t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitOK, code, stderr.String())
+		}
+
+		var results []scanResult
+		if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+			t.Fatalf("failed to decode output: %v\n%s", err, stdout.String())
+		}
+
+		want := map[string]string{
+			"data.probe.computed": statusUnresolved,
+			"data.probe.contacts": statusExists,
+			"data.probe.orders":   statusMissing,
+		}
+		if len(results) != len(want) {
+			t.Fatalf("expected %d results, got %d", len(want), len(results))
+		}
+		for _, r := range results {
+			if r.Status != want[r.Address] {
+				t.Errorf("%s: expected status %q, got %q (%s)", r.Address, want[r.Address], r.Status, r.Detail)
+			}
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := Run(context.Background(), []string{"scan", "-endpoint", server.URL, dir}, &stdout, &stderr)
+		if code != ExitOK {
+			t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitOK, code, stderr.String())
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("expected header and 3 rows, got:\n%s", stdout.String())
+		}
+		if !strings.HasPrefix(lines[0], "ADDRESS") {
+			t.Errorf("expected header row, got %q", lines[0])
+		}
+		if !strings.Contains(lines[2], "exists") || !strings.Contains(lines[2], "arn:aws:dynamodb") {
+			t.Errorf("expected contacts row to show exists and ARN, got %q", lines[2])
+		}
+	})
+}
+
+func TestScan_ExistsIf(t *testing.T) {
+	server := newDynamoDBServer(t, "dev-contacts")
+	server.PutTable(fakeaws.Table{Name: "dev-orders", Status: "DELETING"})
+	dir := writeFiles(t, map[string]string{
+		"main.tf": `
+variable "states" {
+  default = ["creating", "active", "updating"]
+}
+
+data "probe" "contacts" {
+  type      = "aws_dynamodb_table"
+  id        = "dev-contacts"
+  exists_if = var.states
+}
+
+data "probe" "orders" {
+  type      = "aws_dynamodb_table"
+  id        = "dev-orders"
+  exists_if = var.states
+}
+
+data "probe" "raw" {
+  type = "aws_dynamodb_table"
+  id   = "dev-orders"
+}
+`,
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), []string{"scan", "-endpoint", server.URL, "-output", "json", dir}, &stdout, &stderr)
+	if code != ExitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", ExitOK, code, stderr.String())
+	}
+
+	var results []scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("failed to decode output: %v\n%s", err, stdout.String())
+	}
+	want := map[string]string{
+		"data.probe.contacts": statusExists,
+		"data.probe.orders":   statusMissing,
+		"data.probe.raw":      statusExists,
+	}
+	for _, r := range results {
+		if r.Status != want[r.Address] {
+			t.Errorf("%s: expected status %q, got %q (%s)", r.Address, want[r.Address], r.Status, r.Detail)
+		}
+	}
+}
+
+func TestScan_UnsupportedTypeFails(t *testing.T) {
+	server := newDynamoDBServer(t, "x")
+	dir := writeFiles(t, map[string]string{
+		"main.tf": `data "probe" "x" {
+  type = "aws_unknown_thing"
+  id   = "x"
+}
+`,
+	})
+	var stdout, stderr bytes.Buffer
+
+	code := Run(context.Background(), []string{"scan", "-endpoint", server.URL, dir}, &stdout, &stderr)
+	if code != ExitError {
+		t.Errorf("expected exit code %d, got %d", ExitError, code)
+	}
+	if !strings.Contains(stdout.String(), statusError) {
+		t.Errorf("expected error row, got %q", stdout.String())
+	}
+}
+
+func TestIsForbidden(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "access denied",
+			err:      &smithy.GenericAPIError{Code: "AccessDenied"},
+			expected: true,
+		},
+		{
+			name:     "access denied exception",
+			err:      &smithy.GenericAPIError{Code: "AccessDeniedException"},
+			expected: true,
+		},
+		{
+			name:     "throttling",
+			err:      &smithy.GenericAPIError{Code: "ThrottlingException"},
+			expected: false,
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("connection refused"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isForbidden(tt.err); got != tt.expected {
+				t.Errorf("isForbidden(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command gentypes generates the table mapping CloudFormation resource type
+// names to hashicorp/aws resource types from a CloudFormation registry
+// listing, the output of script/update-cloudformation-registry:
+//
+//	aws cloudformation list-types --visibility PUBLIC --type RESOURCE --output json
+//
+// Every AWS resource type in the listing gets an entry. Terraform names follow
+// the aws_<service>_<resource> convention, with the exceptions in
+// terraformNames.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// terraformNames lists the CloudFormation types whose hashicorp/aws resource
+// type doesn't follow the aws_<service>_<resource> convention. An empty name
+// leaves the type out, for types whose conventional name belongs to a
+// different hashicorp/aws resource.
+var terraformNames = map[string]string{
+	"AWS::DynamoDB::GlobalTable":                "aws_dynamodb_table",
+	"AWS::EC2::EIP":                             "aws_eip",
+	"AWS::EC2::Instance":                        "aws_instance",
+	"AWS::EC2::InternetGateway":                 "aws_internet_gateway",
+	"AWS::EC2::LaunchTemplate":                  "aws_launch_template",
+	"AWS::EC2::NatGateway":                      "aws_nat_gateway",
+	"AWS::EC2::Route":                           "aws_route",
+	"AWS::EC2::RouteTable":                      "aws_route_table",
+	"AWS::EC2::SecurityGroup":                   "aws_security_group",
+	"AWS::EC2::Subnet":                          "aws_subnet",
+	"AWS::EC2::Volume":                          "aws_ebs_volume",
+	"AWS::EC2::VPC":                             "aws_vpc",
+	"AWS::EC2::VPCEndpoint":                     "aws_vpc_endpoint",
+	"AWS::ElasticLoadBalancingV2::Listener":     "aws_lb_listener",
+	"AWS::ElasticLoadBalancingV2::ListenerRule": "aws_lb_listener_rule",
+	"AWS::ElasticLoadBalancingV2::LoadBalancer": "aws_lb",
+	"AWS::ElasticLoadBalancingV2::TargetGroup":  "aws_lb_target_group",
+	"AWS::Events::EventBus":                     "aws_cloudwatch_event_bus",
+	"AWS::Events::Rule":                         "aws_cloudwatch_event_rule",
+	"AWS::IAM::ManagedPolicy":                   "aws_iam_policy",
+	"AWS::IAM::Policy":                          "", // an inline policy, not aws_iam_policy
+	"AWS::Logs::LogGroup":                       "aws_cloudwatch_log_group",
+	"AWS::RDS::DBCluster":                       "aws_rds_cluster",
+	"AWS::RDS::DBInstance":                      "aws_db_instance",
+	"AWS::SNS::Subscription":                    "aws_sns_topic_subscription",
+	"AWS::StepFunctions::StateMachine":          "aws_sfn_state_machine",
+}
+
+// registryListing is the part of a ListTypes response gentypes reads.
+type registryListing struct {
+	TypeSummaries []struct {
+		Type     string `json:"Type"`
+		TypeName string `json:"TypeName"`
+	} `json:"TypeSummaries"`
+}
+
+func main() {
+	registry := flag.String("registry", "", "CloudFormation ListTypes output to read (- for stdin)")
+	output := flag.String("o", "", "Go file to write (default stdout)")
+	pkg := flag.String("package", "awsprobe", "package name of the generated file")
+	flag.Parse()
+
+	if err := run(*registry, *output, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "gentypes: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(registry, output, pkg string) error {
+	if registry == "" {
+		return fmt.Errorf("-registry is required")
+	}
+
+	var in io.Reader = os.Stdin
+	if registry != "-" {
+		f, err := os.Open(registry)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	types, err := readRegistry(in)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", registry, err)
+	}
+	src, err := generate(pkg, types)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(output, src, 0o644)
+}
+
+// readRegistry returns the AWS resource type names in a registry listing,
+// skipping third-party and non-resource types.
+func readRegistry(r io.Reader) ([]string, error) {
+	var listing registryListing
+	if err := json.NewDecoder(r).Decode(&listing); err != nil {
+		return nil, err
+	}
+
+	var types []string
+	for _, summary := range listing.TypeSummaries {
+		if summary.Type != "" && summary.Type != "RESOURCE" {
+			continue
+		}
+		if strings.HasPrefix(summary.TypeName, "AWS::") && len(strings.Split(summary.TypeName, "::")) == 3 {
+			types = append(types, summary.TypeName)
+		}
+	}
+	return types, nil
+}
+
+// terraformName returns the hashicorp/aws resource type for a CloudFormation
+// type name, or "" if the type is left out.
+func terraformName(cfnType string) string {
+	if name, ok := terraformNames[cfnType]; ok {
+		return name
+	}
+	parts := strings.Split(cfnType, "::")
+	return "aws_" + strings.ToLower(parts[1]) + "_" + snakeCase(parts[2])
+}
+
+// snakeCase converts a CloudFormation resource name to snake case, keeping
+// acronyms together (DBInstance becomes db_instance).
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// generate renders the cloudFormationTypes table for types.
+func generate(pkg string, types []string) ([]byte, error) {
+	sort.Strings(types)
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Copyright (c) HashiCorp, Inc.")
+	fmt.Fprintln(&buf, "// SPDX-License-Identifier: MPL-2.0")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// Code generated by gentypes from the CloudFormation registry. DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintln(&buf, "// cloudFormationTypes maps CloudFormation resource type names to the")
+	fmt.Fprintln(&buf, "// hashicorp/aws resource type that manages the same resource.")
+	fmt.Fprintln(&buf, "var cloudFormationTypes = map[string]string{")
+	for i, cfnType := range types {
+		if i > 0 && cfnType == types[i-1] {
+			continue
+		}
+		if name := terraformName(cfnType); name != "" {
+			fmt.Fprintf(&buf, "\t%q: %q,\n", cfnType, name)
+		}
+	}
+	fmt.Fprintln(&buf, "}")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadRegistry(t *testing.T) {
+	types, err := readRegistry(strings.NewReader(`{"TypeSummaries": [
+		{"Type": "RESOURCE", "TypeName": "AWS::S3::Bucket"},
+		{"Type": "RESOURCE", "TypeName": "Example::Widget::Gadget"},
+		{"Type": "HOOK", "TypeName": "AWS::Hooks::Example"},
+		{"TypeName": "AWS::DynamoDB::Table"}
+	]}`))
+	if err != nil {
+		t.Fatalf("readRegistry: %v", err)
+	}
+	if want := []string{"AWS::S3::Bucket", "AWS::DynamoDB::Table"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("readRegistry() = %v, want %v", types, want)
+	}
+}
+
+func TestTerraformName(t *testing.T) {
+	tests := map[string]string{
+		"AWS::S3::Bucket":                           "aws_s3_bucket",
+		"AWS::DynamoDB::Table":                      "aws_dynamodb_table",
+		"AWS::Lambda::EventSourceMapping":           "aws_lambda_event_source_mapping",
+		"AWS::EC2::VPC":                             "aws_vpc",
+		"AWS::DynamoDB::GlobalTable":                "aws_dynamodb_table",
+		"AWS::ElasticLoadBalancingV2::LoadBalancer": "aws_lb",
+		"AWS::IAM::Policy":                          "",
+		"AWS::Example::DBProxyEndpoint":             "aws_example_db_proxy_endpoint",
+		"AWS::Example::IPv6Block":                   "aws_example_i_pv6_block",
+	}
+	for cfnType, want := range tests {
+		if got := terraformName(cfnType); got != want {
+			t.Errorf("terraformName(%q) = %q, want %q", cfnType, got, want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := generate("provider", []string{"AWS::S3::Bucket", "AWS::DynamoDB::Table", "AWS::S3::Bucket", "AWS::IAM::Policy"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	out := string(src)
+	if !strings.Contains(out, "DO NOT EDIT") || !strings.Contains(out, "package provider") {
+		t.Errorf("missing generated header:\n%s", out)
+	}
+	if strings.Count(out, `"AWS::S3::Bucket"`) != 1 {
+		t.Errorf("expected duplicates to be dropped:\n%s", out)
+	}
+	if strings.Contains(out, "AWS::IAM::Policy") {
+		t.Errorf("expected AWS::IAM::Policy to be left out:\n%s", out)
+	}
+	if strings.Index(out, "AWS::DynamoDB::Table") > strings.Index(out, "AWS::S3::Bucket") {
+		t.Errorf("expected sorted entries:\n%s", out)
+	}
+}
+
+// A type new to the registry gets an entry from the listing alone.
+func TestRun_NewRegistryType(t *testing.T) {
+	dir := t.TempDir()
+	registry := filepath.Join(dir, "registry.json")
+	listing := `{"TypeSummaries": [
+		{"Type": "RESOURCE", "TypeName": "AWS::S3::Bucket"},
+		{"Type": "RESOURCE", "TypeName": "AWS::Widgets::WidgetPool"}
+	]}`
+	if err := os.WriteFile(registry, []byte(listing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "types.go")
+	if err := run(registry, output, "provider"); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	src, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), `"AWS::Widgets::WidgetPool": "aws_widgets_widget_pool"`) {
+		t.Errorf("expected an entry for the new type:\n%s", src)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeaws
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Table is a seeded DynamoDB table. Zero values are filled with plausible
+// defaults when the table is seeded.
+type Table struct {
+	// Name is the table name.
+	Name string
+
+	// Arn defaults to an ARN in Region and AccountID.
+	Arn string
+
+	// Status defaults to ACTIVE.
+	Status string
+
+	// HashKey defaults to "id", a string attribute.
+	HashKey string
+
+	// DeletionProtection reports DeletionProtectionEnabled.
+	DeletionProtection bool
+
+	// Tags are returned by ListTagsOfResource.
+	Tags map[string]string
+
+	// CreatedAt defaults to the time the table was seeded.
+	CreatedAt time.Time
+}
+
+// PutTable seeds a table, replacing any existing one with the same name.
+func (s *Server) PutTable(table Table) {
+	if table.Arn == "" {
+		table.Arn = fmt.Sprintf("arn:aws:dynamodb:%s:%s:table/%s", Region, AccountID, table.Name)
+	}
+	if table.Status == "" {
+		table.Status = "ACTIVE"
+	}
+	if table.HashKey == "" {
+		table.HashKey = "id"
+	}
+	if table.CreatedAt.IsZero() {
+		table.CreatedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tables[table.Name] = table
+}
+
+// DeleteTable removes a seeded table.
+func (s *Server) DeleteTable(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tables, name)
+}
+
+// serveDynamoDB handles DynamoDB JSON 1.0 requests.
+func (s *Server) serveDynamoDB(w http.ResponseWriter, r *http.Request) {
+	operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_20120810.")
+
+	var input struct {
+		TableName   string
+		ResourceArn string
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeDynamoDBError(w, Error{Status: http.StatusBadRequest, Code: "SerializationException", Message: err.Error()})
+		return
+	}
+
+	switch operation {
+	case OpDescribeTable, OpListTagsOfResource:
+	default:
+		writeDynamoDBError(w, Error{Status: http.StatusBadRequest, Code: "UnknownOperationException", Message: "operation not supported by fakeaws"})
+		return
+	}
+
+	if err := s.record(operation); err != nil {
+		writeDynamoDBError(w, *err)
+		return
+	}
+
+	s.mu.Lock()
+	var table Table
+	found := false
+	for _, t := range s.tables {
+		if t.Name == input.TableName || (input.ResourceArn != "" && t.Arn == input.ResourceArn) {
+			table, found = t, true
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if !found {
+		name := input.TableName
+		if name == "" {
+			name = input.ResourceArn
+		}
+		writeDynamoDBError(w, Error{
+			Status:  http.StatusBadRequest,
+			Code:    "ResourceNotFoundException",
+			Message: fmt.Sprintf("Requested resource not found: Table: %s not found", name),
+		})
+		return
+	}
+
+	switch operation {
+	case OpDescribeTable:
+		writeJSON(w, http.StatusOK, map[string]any{
+			"Table": map[string]any{
+				"TableName":                 table.Name,
+				"TableArn":                  table.Arn,
+				"TableId":                   "00000000-0000-0000-0000-000000000000",
+				"TableStatus":               table.Status,
+				"CreationDateTime":          float64(table.CreatedAt.Unix()),
+				"ItemCount":                 0,
+				"TableSizeBytes":            0,
+				"DeletionProtectionEnabled": table.DeletionProtection,
+				"BillingModeSummary": map[string]any{
+					"BillingMode": "PAY_PER_REQUEST",
+				},
+				"ProvisionedThroughput": map[string]any{
+					"ReadCapacityUnits":      0,
+					"WriteCapacityUnits":     0,
+					"NumberOfDecreasesToday": 0,
+				},
+				"KeySchema": []map[string]any{
+					{"AttributeName": table.HashKey, "KeyType": "HASH"},
+				},
+				"AttributeDefinitions": []map[string]any{
+					{"AttributeName": table.HashKey, "AttributeType": "S"},
+				},
+			},
+		})
+
+	case OpListTagsOfResource:
+		tags := make([]map[string]string, 0, len(table.Tags))
+		for _, key := range sortedKeys(table.Tags) {
+			tags = append(tags, map[string]string{"Key": key, "Value": table.Tags[key]})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"Tags": tags})
+	}
+}
+
+// writeDynamoDBError writes a JSON protocol error.
+func writeDynamoDBError(w http.ResponseWriter, err Error) {
+	w.Header().Set("X-Amzn-ErrorType", err.Code)
+	writeJSON(w, err.Status, map[string]string{
+		"__type":  "com.amazonaws.dynamodb.v20120810#" + err.Code,
+		"message": err.Message,
+	})
+}
+
+// writeJSON writes v as an AWS JSON 1.0 document with the CRC32 checksum
+// header DynamoDB clients validate.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.Header().Set("X-Amz-Crc32", strconv.FormatUint(uint64(crc32.ChecksumIEEE(body)), 10))
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeaws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Evaluation decisions returned by SimulatePrincipalPolicy.
+const (
+	DecisionAllowed      = "allowed"
+	DecisionExplicitDeny = "explicitDeny"
+	DecisionImplicitDeny = "implicitDeny"
+)
+
+// Principal is a seeded IAM principal for policy simulation.
+type Principal struct {
+	// Decisions maps action names to their evaluation decision. Actions
+	// that aren't listed are implicitly denied.
+	Decisions map[string]string
+
+	// PolicyID is reported as the matched statement's source policy for
+	// actions that aren't implicitly denied.
+	PolicyID string
+
+	// MissingContextKeys are reported for every evaluated action.
+	MissingContextKeys []string
+}
+
+// PutPrincipal seeds a principal, replacing any existing one with the same
+// ARN.
+func (s *Server) PutPrincipal(arn string, principal Principal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.principals[arn] = principal
+}
+
+// SetSimulationPageSize limits how many evaluation results are returned per
+// SimulatePrincipalPolicy page, to exercise pagination. Zero disables
+// paging.
+func (s *Server) SetSimulationPageSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pageSize = n
+}
+
+type iamSimulateResponse struct {
+	XMLName   xml.Name          `xml:"https://iam.amazonaws.com/doc/2010-05-08/ SimulatePrincipalPolicyResponse"`
+	Result    iamSimulateResult `xml:"SimulatePrincipalPolicyResult"`
+	RequestID string            `xml:"ResponseMetadata>RequestId"`
+}
+
+type iamSimulateResult struct {
+	EvaluationResults []iamEvaluationResult `xml:"EvaluationResults>member"`
+	IsTruncated       bool                  `xml:"IsTruncated"`
+	Marker            string                `xml:"Marker,omitempty"`
+}
+
+type iamEvaluationResult struct {
+	EvalActionName       string                `xml:"EvalActionName"`
+	EvalResourceName     string                `xml:"EvalResourceName"`
+	EvalDecision         string                `xml:"EvalDecision"`
+	MatchedStatements    []iamMatchedStatement `xml:"MatchedStatements>member"`
+	MissingContextValues []string              `xml:"MissingContextValues>member"`
+}
+
+type iamMatchedStatement struct {
+	SourcePolicyID   string `xml:"SourcePolicyId"`
+	SourcePolicyType string `xml:"SourcePolicyType"`
+}
+
+type iamErrorResponse struct {
+	XMLName   xml.Name `xml:"ErrorResponse"`
+	Type      string   `xml:"Error>Type"`
+	Code      string   `xml:"Error>Code"`
+	Message   string   `xml:"Error>Message"`
+	RequestID string   `xml:"RequestId"`
+}
+
+// serveIAM handles IAM query protocol requests.
+func (s *Server) serveIAM(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeIAMError(w, Error{Status: http.StatusBadRequest, Code: "MalformedInput", Message: err.Error()})
+		return
+	}
+
+	operation := r.PostForm.Get("Action")
+	if operation != OpSimulatePrincipalPolicy {
+		writeIAMError(w, Error{Status: http.StatusBadRequest, Code: "InvalidAction", Message: fmt.Sprintf("action %s not supported by fakeaws", operation)})
+		return
+	}
+
+	if err := s.record(operation); err != nil {
+		writeIAMError(w, *err)
+		return
+	}
+
+	arn := r.PostForm.Get("PolicySourceArn")
+
+	s.mu.Lock()
+	principal, ok := s.principals[arn]
+	pageSize := s.pageSize
+	s.mu.Unlock()
+
+	if !ok {
+		writeIAMError(w, Error{
+			Status:  http.StatusNotFound,
+			Code:    "NoSuchEntity",
+			Message: fmt.Sprintf("The user with name %s cannot be found.", arn),
+		})
+		return
+	}
+
+	actions := formList(r, "ActionNames")
+	resources := formList(r, "ResourceArns")
+	if len(resources) == 0 {
+		resources = []string{"*"}
+	}
+
+	var results []iamEvaluationResult
+	for _, action := range actions {
+		for _, resource := range resources {
+			decision, ok := principal.Decisions[action]
+			if !ok {
+				decision = DecisionImplicitDeny
+			}
+			result := iamEvaluationResult{
+				EvalActionName:       action,
+				EvalResourceName:     resource,
+				EvalDecision:         decision,
+				MissingContextValues: principal.MissingContextKeys,
+			}
+			if decision != DecisionImplicitDeny && principal.PolicyID != "" {
+				result.MatchedStatements = []iamMatchedStatement{
+					{SourcePolicyID: principal.PolicyID, SourcePolicyType: "user"},
+				}
+			}
+			results = append(results, result)
+		}
+	}
+
+	start := 0
+	if marker := r.PostForm.Get("Marker"); marker != "" {
+		start, _ = strconv.Atoi(marker)
+	}
+	if start > len(results) {
+		start = len(results)
+	}
+
+	page := iamSimulateResult{EvaluationResults: results[start:]}
+	if pageSize > 0 && len(page.EvaluationResults) > pageSize {
+		page.EvaluationResults = page.EvaluationResults[:pageSize]
+		page.IsTruncated = true
+		page.Marker = strconv.Itoa(start + pageSize)
+	}
+
+	writeXML(w, http.StatusOK, iamSimulateResponse{Result: page, RequestID: "fakeaws"})
+}
+
+// formList collects a query protocol list parameter (Name.member.1,
+// Name.member.2, ...).
+func formList(r *http.Request, name string) []string {
+	var values []string
+	for i := 1; ; i++ {
+		key := fmt.Sprintf("%s.member.%d", name, i)
+		if !r.PostForm.Has(key) {
+			return values
+		}
+		values = append(values, r.PostForm.Get(key))
+	}
+}
+
+// writeIAMError writes a query protocol error.
+func writeIAMError(w http.ResponseWriter, err Error) {
+	errType := "Sender"
+	if err.Status >= http.StatusInternalServerError {
+		errType = "Receiver"
+	}
+	writeXML(w, err.Status, iamErrorResponse{
+		Type:      errType,
+		Code:      err.Code,
+		Message:   err.Message,
+		RequestID: "fakeaws",
+	})
+}
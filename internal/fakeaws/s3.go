@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeaws
+
+import (
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Bucket is a seeded S3 bucket.
+type Bucket struct {
+	// Region is the bucket's region. Empty means us-east-1, which S3
+	// reports as an empty LocationConstraint.
+	Region string
+
+	// Tags are the bucket tags. GetBucketTagging returns NoSuchTagSet when
+	// there are none.
+	Tags map[string]string
+}
+
+// PutBucket seeds a bucket, replacing any existing one with the same name.
+func (s *Server) PutBucket(name string, bucket Bucket) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buckets[name] = bucket
+}
+
+// DeleteBucket removes a seeded bucket.
+func (s *Server) DeleteBucket(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.buckets, name)
+}
+
+type s3LocationConstraint struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+	Value   string   `xml:",chardata"`
+}
+
+type s3Tagging struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ Tagging"`
+	TagSet  []s3Tag  `xml:"TagSet>Tag"`
+}
+
+type s3Tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+type s3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+// serveS3 handles path-style bucket requests: HEAD /bucket, GET
+// /bucket?location and GET /bucket?tagging.
+func (s *Server) serveS3(w http.ResponseWriter, r *http.Request) {
+	name := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)[0]
+	query := r.URL.Query()
+
+	var operation string
+	switch {
+	case r.Method == http.MethodHead:
+		operation = OpHeadBucket
+	case r.Method == http.MethodGet && query.Has("location"):
+		operation = OpGetBucketLocation
+	case r.Method == http.MethodGet && query.Has("tagging"):
+		operation = OpGetBucketTagging
+	default:
+		writeS3Error(w, r, Error{Status: http.StatusNotImplemented, Code: "NotImplemented", Message: "operation not supported by fakeaws"})
+		return
+	}
+
+	if err := s.record(operation); err != nil {
+		writeS3Error(w, r, *err)
+		return
+	}
+
+	s.mu.Lock()
+	bucket, ok := s.buckets[name]
+	s.mu.Unlock()
+
+	if !ok {
+		writeS3Error(w, r, Error{Status: http.StatusNotFound, Code: "NoSuchBucket", Message: "The specified bucket does not exist"})
+		return
+	}
+
+	switch operation {
+	case OpHeadBucket:
+		region := bucket.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		w.Header().Set("X-Amz-Bucket-Region", region)
+		w.WriteHeader(http.StatusOK)
+
+	case OpGetBucketLocation:
+		location := bucket.Region
+		if location == "us-east-1" {
+			location = ""
+		}
+		writeXML(w, http.StatusOK, s3LocationConstraint{Value: location})
+
+	case OpGetBucketTagging:
+		if len(bucket.Tags) == 0 {
+			writeS3Error(w, r, Error{Status: http.StatusNotFound, Code: "NoSuchTagSet", Message: "The TagSet does not exist"})
+			return
+		}
+		tagging := s3Tagging{}
+		for _, key := range sortedKeys(bucket.Tags) {
+			tagging.TagSet = append(tagging.TagSet, s3Tag{Key: key, Value: bucket.Tags[key]})
+		}
+		writeXML(w, http.StatusOK, tagging)
+	}
+}
+
+// writeS3Error writes an S3 REST error. HEAD responses carry no body, so
+// clients only see the status code.
+func writeS3Error(w http.ResponseWriter, r *http.Request, err Error) {
+	if r.Method == http.MethodHead {
+		w.WriteHeader(err.Status)
+		return
+	}
+	writeXML(w, err.Status, s3Error{Code: err.Code, Message: err.Message})
+}
+
+// writeXML writes v as an XML document.
+func writeXML(w http.ResponseWriter, status int, v any) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(body)
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package fakeaws is an in-process fake of the AWS APIs the provider calls,
+// for hermetic tests. A single httptest server speaks enough of the S3 REST,
+// DynamoDB JSON and IAM and STS query protocols to serve HeadBucket,
+// GetBucketLocation, GetBucketTagging, DescribeTable, ListTagsOfResource,
+// SimulatePrincipalPolicy and GetCallerIdentity. Tests seed resources,
+// script errors per operation and point the provider's endpoint attribute at
+// URL.
+package fakeaws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Operation names accepted by Fail and FailN.
+const (
+	OpHeadBucket              = "HeadBucket"
+	OpGetBucketLocation       = "GetBucketLocation"
+	OpGetBucketTagging        = "GetBucketTagging"
+	OpDescribeTable           = "DescribeTable"
+	OpListTagsOfResource      = "ListTagsOfResource"
+	OpSimulatePrincipalPolicy = "SimulatePrincipalPolicy"
+	OpGetCallerIdentity       = "GetCallerIdentity"
+)
+
+// Region and AccountID are used to build ARNs for seeded resources.
+const (
+	Region    = "us-east-1"
+	AccountID = "123456789012"
+)
+
+// Error is a scripted API error. It is encoded in the protocol of the
+// service that receives the failing request.
+type Error struct {
+	// Status is the HTTP status code.
+	Status int
+
+	// Code is the AWS error code (e.g., ThrottlingException).
+	Code string
+
+	// Message is the human-readable error message.
+	Message string
+}
+
+// fault is a scripted error with a remaining count; a negative count never
+// runs out.
+type fault struct {
+	err       Error
+	remaining int
+}
+
+// Server is a fake AWS endpoint backed by in-memory state.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	buckets    map[string]Bucket
+	tables     map[string]Table
+	principals map[string]Principal
+	faults     map[string]*fault
+	calls      []string
+
+	// pageSize limits the number of simulation results per page; zero
+	// returns everything in one page.
+	pageSize int
+}
+
+// New starts a fake server that is closed when the test finishes.
+func New(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		buckets:    make(map[string]Bucket),
+		tables:     make(map[string]Table),
+		principals: make(map[string]Principal),
+		faults:     make(map[string]*fault),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// ProviderConfig returns a provider block that points the probe provider at
+// the server.
+func (s *Server) ProviderConfig() string {
+	return fmt.Sprintf(`
+provider "probe" {
+  endpoint = %q
+  region   = %q
+}
+`, s.URL, Region)
+}
+
+// Fail makes every subsequent call to operation return err.
+func (s *Server) Fail(operation string, err Error) {
+	s.FailN(operation, -1, err)
+}
+
+// FailN makes the next n calls to operation return err. Later calls succeed
+// again.
+func (s *Server) FailN(operation string, n int, err Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults[operation] = &fault{err: err, remaining: n}
+}
+
+// ClearFaults removes all scripted errors.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = make(map[string]*fault)
+}
+
+// Calls returns the operations the server has received, in order.
+func (s *Server) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.calls...)
+}
+
+// CallCount returns how many times operation has been called.
+func (s *Server) CallCount(operation string) int {
+	count := 0
+	for _, call := range s.Calls() {
+		if call == operation {
+			count++
+		}
+	}
+	return count
+}
+
+// record logs a call and returns the scripted error for it, if any.
+func (s *Server) record(operation string) *Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, operation)
+
+	f, ok := s.faults[operation]
+	if !ok || f.remaining == 0 {
+		return nil
+	}
+	if f.remaining > 0 {
+		f.remaining--
+	}
+	err := f.err
+	return &err
+}
+
+// serveHTTP routes a request to the service that owns it. DynamoDB requests
+// carry an X-Amz-Target header, IAM and STS requests are form posts with an
+// Action, and everything else is treated as a path-style S3 request.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_"):
+		s.serveDynamoDB(w, r)
+	case r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded"):
+		if r.FormValue("Action") == OpGetCallerIdentity {
+			s.serveSTS(w, r)
+			return
+		}
+		s.serveIAM(w, r)
+	default:
+		s.serveS3(w, r)
+	}
+}
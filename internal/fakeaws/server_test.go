@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeaws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+)
+
+func testConfig(s *Server) aws.Config {
+	return aws.Config{
+		Region:       Region,
+		BaseEndpoint: aws.String(s.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+		Retryer: func() aws.Retryer {
+			return aws.NopRetryer{}
+		},
+	}
+}
+
+func TestSimulatePrincipalPolicy_Pagination(t *testing.T) {
+	s := New(t)
+	s.PutPrincipal("arn:aws:iam::123456789012:user/alice", Principal{
+		Decisions: map[string]string{"s3:GetObject": DecisionAllowed},
+	})
+	s.SetSimulationPageSize(2)
+
+	client := iam.NewFromConfig(testConfig(s))
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(client, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String("arn:aws:iam::123456789012:user/alice"),
+		ActionNames:     []string{"s3:GetObject", "s3:PutObject", "s3:DeleteObject"},
+	})
+
+	var decisions []string
+	pages := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pages++
+		for _, result := range page.EvaluationResults {
+			decisions = append(decisions, aws.ToString(result.EvalActionName)+"="+string(result.EvalDecision))
+		}
+	}
+
+	if pages != 2 {
+		t.Errorf("expected 2 pages, got %d", pages)
+	}
+	want := []string{"s3:GetObject=allowed", "s3:PutObject=implicitDeny", "s3:DeleteObject=implicitDeny"}
+	if len(decisions) != len(want) {
+		t.Fatalf("decisions = %v, want %v", decisions, want)
+	}
+	for i := range want {
+		if decisions[i] != want[i] {
+			t.Errorf("decisions[%d] = %q, want %q", i, decisions[i], want[i])
+		}
+	}
+}
+
+func TestFailN(t *testing.T) {
+	s := New(t)
+	s.PutPrincipal("arn:aws:iam::123456789012:user/alice", Principal{})
+	s.FailN(OpSimulatePrincipalPolicy, 1, Error{Status: http.StatusForbidden, Code: "AccessDenied", Message: "denied"})
+
+	client := iam.NewFromConfig(testConfig(s))
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String("arn:aws:iam::123456789012:user/alice"),
+		ActionNames:     []string{"s3:GetObject"},
+	}
+
+	_, err := client.SimulatePrincipalPolicy(context.Background(), input)
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "AccessDenied" {
+		t.Fatalf("expected scripted AccessDenied, got %v", err)
+	}
+
+	if _, err := client.SimulatePrincipalPolicy(context.Background(), input); err != nil {
+		t.Fatalf("expected second call to succeed, got %v", err)
+	}
+
+	if got := s.CallCount(OpSimulatePrincipalPolicy); got != 2 {
+		t.Errorf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestSimulatePrincipalPolicy_NoSuchEntity(t *testing.T) {
+	s := New(t)
+
+	client := iam.NewFromConfig(testConfig(s))
+	_, err := client.SimulatePrincipalPolicy(context.Background(), &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String("arn:aws:iam::123456789012:user/nobody"),
+		ActionNames:     []string{"s3:GetObject"},
+	})
+
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "NoSuchEntity" {
+		t.Fatalf("expected NoSuchEntity, got %v", err)
+	}
+}
+
+func TestGetCallerIdentity(t *testing.T) {
+	s := New(t)
+
+	client := sts.NewFromConfig(testConfig(s))
+	out, err := client.GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if aws.ToString(out.Account) != AccountID || aws.ToString(out.Arn) != CallerArn {
+		t.Errorf("unexpected identity: %s, %s", aws.ToString(out.Account), aws.ToString(out.Arn))
+	}
+	if s.CallCount(OpGetCallerIdentity) != 1 {
+		t.Errorf("expected one call, got %v", s.Calls())
+	}
+}
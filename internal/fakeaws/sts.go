@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeaws
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// CallerArn is the identity GetCallerIdentity reports.
+const CallerArn = "arn:aws:iam::" + AccountID + ":user/fakeaws"
+
+type stsCallerIdentityResponse struct {
+	XMLName   xml.Name                `xml:"https://sts.amazonaws.com/doc/2011-06-15/ GetCallerIdentityResponse"`
+	Result    stsCallerIdentityResult `xml:"GetCallerIdentityResult"`
+	RequestID string                  `xml:"ResponseMetadata>RequestId"`
+}
+
+type stsCallerIdentityResult struct {
+	Account string `xml:"Account"`
+	Arn     string `xml:"Arn"`
+	UserID  string `xml:"UserId"`
+}
+
+// serveSTS handles STS query protocol requests, which share the IAM error
+// format.
+func (s *Server) serveSTS(w http.ResponseWriter, r *http.Request) {
+	if err := s.record(OpGetCallerIdentity); err != nil {
+		writeIAMError(w, *err)
+		return
+	}
+
+	writeXML(w, http.StatusOK, stsCallerIdentityResponse{
+		Result: stsCallerIdentityResult{
+			Account: AccountID,
+			Arn:     CallerArn,
+			UserID:  "AIDAFAKEAWS",
+		},
+		RequestID: "fakeaws",
+	})
+}
@@ -0,0 +1,325 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package faults is an HTTP transport that injects failures into AWS calls,
+// so tests can exercise retry and error classification paths that real
+// endpoints rarely produce. Faults are keyed by service and operation and
+// are independent of the backend the transport forwards to.
+package faults
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+)
+
+// Fault alters a single round trip. It may answer the request itself or
+// forward it with next and modify the response.
+type Fault func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// Latency delays the request by d before forwarding it. The delay is cut
+// short if the request's context is done.
+func Latency(d time.Duration) Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			return next.RoundTrip(req)
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// ConnectionReset fails the request as if the server reset the connection.
+func ConnectionReset() Fault {
+	return func(req *http.Request, _ http.RoundTripper) (*http.Response, error) {
+		return nil, &url.Error{
+			Op:  req.Method,
+			URL: req.URL.String(),
+			Err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET},
+		}
+	}
+}
+
+// Status answers the request with an API error in the request's protocol.
+func Status(status int, code, message string) Fault {
+	return func(req *http.Request, _ http.RoundTripper) (*http.Response, error) {
+		return errorResponse(req, status, code, message), nil
+	}
+}
+
+// Throttled answers with 429 Too Many Requests.
+func Throttled() Fault {
+	return Status(http.StatusTooManyRequests, "ThrottlingException", "Rate exceeded")
+}
+
+// Unavailable answers with 503 Service Unavailable.
+func Unavailable() Fault {
+	return Status(http.StatusServiceUnavailable, "ServiceUnavailable", "Service is unavailable")
+}
+
+// ExpiredCredentials answers with the error AWS returns for an expired
+// session token.
+func ExpiredCredentials() Fault {
+	return func(req *http.Request, _ http.RoundTripper) (*http.Response, error) {
+		if protocol(req) == protocolJSON {
+			return errorResponse(req, http.StatusBadRequest, "ExpiredTokenException", "The security token included in the request is expired"), nil
+		}
+		return errorResponse(req, http.StatusForbidden, "ExpiredToken", "The provided token has expired."), nil
+	}
+}
+
+// MalformedBody forwards the request and truncates the response body, as
+// if the connection dropped mid-response.
+func MalformedBody() Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		body = body[:len(body)/2]
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+		resp.Header.Del("Content-Length")
+		resp.Header.Del("X-Amz-Crc32")
+		return resp, nil
+	}
+}
+
+// rule is an injected fault with a remaining count; a negative count never
+// runs out.
+type rule struct {
+	service   string
+	operation string
+	fault     Fault
+	remaining int
+}
+
+// Transport is an http.RoundTripper that applies the first matching fault
+// to each request and forwards the rest unchanged.
+type Transport struct {
+	base http.RoundTripper
+
+	mu    sync.Mutex
+	rules []*rule
+	calls map[string]int
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, calls: make(map[string]int)}
+}
+
+// Client returns an HTTP client using the transport, suitable for
+// aws.Config.HTTPClient.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Inject applies fault to every call matching service and operation. An
+// empty service or operation matches any. Services match the SDK service ID
+// or signing name case-insensitively (e.g., "dynamodb", "s3", "iam").
+func (t *Transport) Inject(service, operation string, fault Fault) {
+	t.InjectN(service, operation, -1, fault)
+}
+
+// InjectN applies fault to the next n matching calls.
+func (t *Transport) InjectN(service, operation string, n int, fault Fault) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rules = append(t.rules, &rule{service: service, operation: operation, fault: fault, remaining: n})
+}
+
+// Reset removes all faults and call counts.
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rules = nil
+	t.calls = make(map[string]int)
+}
+
+// Calls returns how many requests the transport has seen for an operation,
+// including those that failed.
+func (t *Transport) Calls(operation string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.calls[operation]
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service, operation := identify(req)
+
+	t.mu.Lock()
+	t.calls[operation]++
+	var fault Fault
+	for _, r := range t.rules {
+		if r.remaining == 0 || !matchService(r.service, service) || (r.operation != "" && r.operation != operation) {
+			continue
+		}
+		if r.remaining > 0 {
+			r.remaining--
+		}
+		fault = r.fault
+		break
+	}
+	t.mu.Unlock()
+
+	if fault == nil {
+		return t.base.RoundTrip(req)
+	}
+	return fault(req, t.base)
+}
+
+// matchService compares a rule's service with the request's service IDs.
+func matchService(want string, have []string) bool {
+	if want == "" {
+		return true
+	}
+	for _, h := range have {
+		if strings.EqualFold(want, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// identify returns the service names and operation of an AWS request. SDK
+// clients record them in the request context; other callers are identified
+// from the SigV4 credential scope and the protocol's operation field.
+func identify(req *http.Request) ([]string, string) {
+	ctx := req.Context()
+	var services []string
+	if id := awsmiddleware.GetServiceID(ctx); id != "" {
+		services = append(services, id)
+	}
+	if name := awsmiddleware.GetSigningName(ctx); name != "" {
+		services = append(services, name)
+	}
+	if name := signingName(req); name != "" {
+		services = append(services, name)
+	}
+
+	operation := awsmiddleware.GetOperationName(ctx)
+	if operation == "" {
+		operation = operationFromRequest(req)
+	}
+
+	return services, operation
+}
+
+// signingName extracts the service from the Authorization header's
+// credential scope (Credential=AKID/date/region/service/aws4_request).
+func signingName(req *http.Request) string {
+	auth := req.Header.Get("Authorization")
+	_, after, ok := strings.Cut(auth, "Credential=")
+	if !ok {
+		return ""
+	}
+	scope, _, _ := strings.Cut(after, ",")
+	parts := strings.Split(scope, "/")
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[3]
+}
+
+// operationFromRequest reads the operation from an X-Amz-Target header or
+// a query protocol Action parameter.
+func operationFromRequest(req *http.Request) string {
+	if target := req.Header.Get("X-Amz-Target"); target != "" {
+		if i := strings.LastIndex(target, "."); i >= 0 {
+			return target[i+1:]
+		}
+		return target
+	}
+
+	if protocol(req) == protocolQuery && req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if err == nil {
+			if values, err := url.ParseQuery(string(body)); err == nil {
+				return values.Get("Action")
+			}
+		}
+	}
+
+	return ""
+}
+
+const (
+	protocolJSON  = "json"
+	protocolQuery = "query"
+	protocolREST  = "rest-xml"
+)
+
+// protocol guesses the wire protocol of req.
+func protocol(req *http.Request) string {
+	switch {
+	case req.Header.Get("X-Amz-Target") != "":
+		return protocolJSON
+	case strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded"):
+		return protocolQuery
+	default:
+		return protocolREST
+	}
+}
+
+// errorResponse builds an error response in the protocol of req.
+func errorResponse(req *http.Request, status int, code, message string) *http.Response {
+	header := make(http.Header)
+	var body string
+
+	switch protocol(req) {
+	case protocolJSON:
+		header.Set("Content-Type", "application/x-amz-json-1.0")
+		header.Set("X-Amzn-ErrorType", code)
+		body = fmt.Sprintf(`{"__type":%q,"message":%q}`, code, message)
+	case protocolQuery:
+		header.Set("Content-Type", "text/xml")
+		body = fmt.Sprintf(`<ErrorResponse><Error><Type>Sender</Type><Code>%s</Code><Message>%s</Message></Error><RequestId>faults</RequestId></ErrorResponse>`, code, message)
+	default:
+		header.Set("Content-Type", "application/xml")
+		if req.Method != http.MethodHead {
+			body = fmt.Sprintf(`<Error><Code>%s</Code><Message>%s</Message></Error>`, code, message)
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package faults
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+)
+
+// testConfig returns a config that sends requests to s through transport
+// without retrying.
+func testConfig(s *fakeaws.Server, transport *Transport) aws.Config {
+	return aws.Config{
+		Region:       fakeaws.Region,
+		BaseEndpoint: aws.String(s.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+		HTTPClient:   transport.Client(),
+		Retryer: func() aws.Retryer {
+			return aws.NopRetryer{}
+		},
+	}
+}
+
+func TestTransport_MatchesServiceAndOperation(t *testing.T) {
+	s := fakeaws.New(t)
+	s.PutTable(fakeaws.Table{Name: "orders"})
+	s.PutBucket("assets", fakeaws.Bucket{})
+
+	transport := NewTransport(nil)
+	transport.Inject("dynamodb", fakeaws.OpListTagsOfResource, Unavailable())
+	cfg := testConfig(s, transport)
+
+	ddb := dynamodb.NewFromConfig(cfg)
+	if _, err := ddb.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String("orders")}); err != nil {
+		t.Fatalf("DescribeTable should not be faulted: %v", err)
+	}
+
+	_, err := ddb.ListTagsOfResource(context.Background(), &dynamodb.ListTagsOfResourceInput{
+		ResourceArn: aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/orders"),
+	})
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ServiceUnavailable" {
+		t.Fatalf("expected ServiceUnavailable, got %v", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) { o.UsePathStyle = true })
+	if _, err := client.HeadBucket(context.Background(), &s3.HeadBucketInput{Bucket: aws.String("assets")}); err != nil {
+		t.Fatalf("S3 should not be faulted: %v", err)
+	}
+
+	if got := transport.Calls(fakeaws.OpListTagsOfResource); got != 1 {
+		t.Errorf("expected 1 ListTagsOfResource call, got %d", got)
+	}
+	if got := s.CallCount(fakeaws.OpListTagsOfResource); got != 0 {
+		t.Errorf("faulted call should not reach the server, got %d", got)
+	}
+}
+
+func TestTransport_InjectN(t *testing.T) {
+	s := fakeaws.New(t)
+	s.PutBucket("assets", fakeaws.Bucket{})
+
+	transport := NewTransport(nil)
+	transport.InjectN("s3", fakeaws.OpHeadBucket, 1, Throttled())
+	client := s3.NewFromConfig(testConfig(s, transport), func(o *s3.Options) { o.UsePathStyle = true })
+	input := &s3.HeadBucketInput{Bucket: aws.String("assets")}
+
+	_, err := client.HeadBucket(context.Background(), input)
+	var respErr interface{ HTTPStatusCode() int }
+	if !errors.As(err, &respErr) || respErr.HTTPStatusCode() != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %v", err)
+	}
+
+	if _, err := client.HeadBucket(context.Background(), input); err != nil {
+		t.Fatalf("expected second call to succeed, got %v", err)
+	}
+
+	transport.Reset()
+	if got := transport.Calls(fakeaws.OpHeadBucket); got != 0 {
+		t.Errorf("expected Reset to clear call counts, got %d", got)
+	}
+}
+
+func TestTransport_ConnectionReset(t *testing.T) {
+	s := fakeaws.New(t)
+
+	transport := NewTransport(nil)
+	transport.Inject("", "", ConnectionReset())
+	client := dynamodb.NewFromConfig(testConfig(s, transport))
+
+	_, err := client.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String("orders")})
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Fatalf("expected ECONNRESET, got %v", err)
+	}
+}
+
+func TestTransport_Latency(t *testing.T) {
+	s := fakeaws.New(t)
+	s.PutTable(fakeaws.Table{Name: "orders"})
+
+	transport := NewTransport(nil)
+	transport.Inject("dynamodb", "", Latency(time.Minute))
+	client := dynamodb.NewFromConfig(testConfig(s, transport))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("orders")})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestTransport_ExpiredCredentials(t *testing.T) {
+	s := fakeaws.New(t)
+	s.PutPrincipal("arn:aws:iam::123456789012:user/alice", fakeaws.Principal{})
+
+	transport := NewTransport(nil)
+	transport.Inject("iam", "SimulatePrincipalPolicy", ExpiredCredentials())
+	client := iam.NewFromConfig(testConfig(s, transport))
+
+	_, err := client.SimulatePrincipalPolicy(context.Background(), &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String("arn:aws:iam::123456789012:user/alice"),
+		ActionNames:     []string{"s3:GetObject"},
+	})
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ExpiredToken" {
+		t.Fatalf("expected ExpiredToken, got %v", err)
+	}
+}
+
+func TestTransport_MalformedBody(t *testing.T) {
+	s := fakeaws.New(t)
+	s.PutTable(fakeaws.Table{Name: "orders"})
+
+	transport := NewTransport(nil)
+	transport.Inject("dynamodb", fakeaws.OpDescribeTable, MalformedBody())
+	client := dynamodb.NewFromConfig(testConfig(s, transport))
+
+	_, err := client.DescribeTable(context.Background(), &dynamodb.DescribeTableInput{TableName: aws.String("orders")})
+	if err == nil {
+		t.Fatal("expected a deserialization error")
+	}
+	if got := s.CallCount(fakeaws.OpDescribeTable); got != 1 {
+		t.Errorf("malformed body should still reach the server once, got %d", got)
+	}
+}
+
+func TestIdentify_FromRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://kinesis.us-east-1.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Amz-Target", "Kinesis_20131202.DescribeStreamSummary")
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=test/20240101/us-east-1/kinesis/aws4_request, SignedHeaders=host, Signature=abc")
+
+	services, operation := identify(req)
+	if operation != "DescribeStreamSummary" {
+		t.Errorf("operation = %q, want DescribeStreamSummary", operation)
+	}
+	if !matchService("kinesis", services) {
+		t.Errorf("services = %v, want kinesis", services)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/shakefu/terraform-provider-probe/probe/awsprobe"
+)
+
+// accountKey identifies credentials at an endpoint; an emulator reports its
+// own account.
+type accountKey struct {
+	credentials string
+	endpoint    string
+}
+
+// accountEntry holds the account of one set of credentials. Its lock is held
+// during the lookup, so concurrent probes wait for a single STS call.
+type accountEntry struct {
+	mu      sync.Mutex
+	account string
+}
+
+// account returns the account cfg's credentials belong to, looking it up
+// with STS GetCallerIdentity once per set of credentials. Failed lookups
+// aren't cached, so a later probe tries again. A nil pool looks the account
+// up on every call.
+func (p *ClientPool) account(ctx context.Context, cfg aws.Config) (string, error) {
+	if p == nil {
+		return lookupAccount(ctx, sts.NewFromConfig(cfg))
+	}
+
+	key := accountKey{
+		credentials: credentialsKey(cfg.Credentials),
+		endpoint:    aws.ToString(cfg.BaseEndpoint),
+	}
+
+	p.mu.Lock()
+	entry, ok := p.accounts[key]
+	if !ok {
+		entry = &accountEntry{}
+		p.accounts[key] = entry
+	}
+	p.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.account == "" {
+		client := pooledClient(p, "sts", cfg, func(cfg aws.Config) *sts.Client {
+			return sts.NewFromConfig(cfg)
+		})
+		account, err := lookupAccount(ctx, client)
+		if err != nil {
+			return "", err
+		}
+		entry.account = account
+	}
+	return entry.account, nil
+}
+
+// lookupAccount calls STS GetCallerIdentity.
+func lookupAccount(ctx context.Context, client *sts.Client) (string, error) {
+	out, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("looking up the caller's account: %w", err)
+	}
+	return aws.ToString(out.Account), nil
+}
+
+// arnBuilder constructs the ARNs of resources reached through an AWS config,
+// in the partition of its region and the account of its credentials.
+// Probers use it for services whose responses don't include ARNs.
+type arnBuilder struct {
+	cfg aws.Config
+}
+
+// newARNBuilder creates an arnBuilder for cfg.
+func newARNBuilder(cfg aws.Config) arnBuilder {
+	return arnBuilder{cfg: cfg}
+}
+
+// Partition returns the partition of the config's region.
+func (b arnBuilder) Partition() string {
+	return awsprobe.Partition(b.cfg.Region)
+}
+
+// Account returns the account of the config's credentials, cached in the
+// provider's client pool if ctx carries one.
+func (b arnBuilder) Account(ctx context.Context) (string, error) {
+	return clientPoolFrom(ctx).account(ctx, b.cfg)
+}
+
+// arnPlaceholder matches the placeholders of an ARN template.
+var arnPlaceholder = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// validateARNTemplate checks that template only uses the placeholders
+// Expand fills in.
+func validateARNTemplate(template string) error {
+	for _, match := range arnPlaceholder.FindAllStringSubmatch(template, -1) {
+		switch match[1] {
+		case "partition", "region", "account", "id":
+		default:
+			return fmt.Errorf("unknown placeholder %s; use ${partition}, ${region}, ${account} or ${id}", match[0])
+		}
+	}
+	return nil
+}
+
+// Expand fills in an ARN template's ${partition}, ${region}, ${account} and
+// ${id} placeholders. The account is only looked up if template uses it.
+func (b arnBuilder) Expand(ctx context.Context, template, identifier string) (string, error) {
+	var account string
+	if strings.Contains(template, "${account}") {
+		var err error
+		if account, err = b.Account(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return strings.NewReplacer(
+		"${partition}", b.Partition(),
+		"${region}", b.cfg.Region,
+		"${account}", account,
+		"${id}", identifier,
+	).Replace(template), nil
+}
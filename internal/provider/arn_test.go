@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+)
+
+func TestARNBuilder(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	ctx := withClientPool(context.Background(), NewClientPool())
+
+	t.Run("global", func(t *testing.T) {
+		gov := cfg.Copy()
+		gov.Region = "us-gov-west-1"
+		got, err := newARNBuilder(gov).Expand(ctx, "arn:${partition}:s3:::${id}", "assets")
+		if err != nil || got != "arn:aws-us-gov:s3:::assets" {
+			t.Errorf("Expand() = %q, %v", got, err)
+		}
+		if server.CallCount(fakeaws.OpGetCallerIdentity) != 0 {
+			t.Error("global ARNs shouldn't look up the account")
+		}
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		cn := cfg.Copy()
+		cn.Region = "cn-north-1"
+		got, err := newARNBuilder(cn).Expand(ctx, "arn:${partition}:iam::${account}:role/${id}", "deploy")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "arn:aws-cn:iam::123456789012:role/deploy" {
+			t.Errorf("Expand() = %q", got)
+		}
+	})
+
+	// The lookup above and the concurrent ones below share one call
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := newARNBuilder(cfg).Account(ctx); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls := server.CallCount(fakeaws.OpGetCallerIdentity); calls != 1 {
+		t.Errorf("expected one GetCallerIdentity call, got %d", calls)
+	}
+}
+
+func TestARNBuilder_AccountError(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.FailN(fakeaws.OpGetCallerIdentity, 1, fakeaws.Error{Status: http.StatusForbidden, Code: "AccessDenied", Message: "not authorized"})
+	ctx := withClientPool(context.Background(), NewClientPool())
+
+	if _, err := newARNBuilder(cfg).Account(ctx); err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Fatalf("expected the lookup to fail, got %v", err)
+	}
+
+	// Failures aren't cached
+	account, err := newARNBuilder(cfg).Account(ctx)
+	if err != nil || account != fakeaws.AccountID {
+		t.Fatalf("expected the account after the failure, got %q, %v", account, err)
+	}
+}
+
+func TestARNBuilder_PoolScope(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	ctx := context.Background()
+
+	// Without a pool, and with separate pools, every lookup calls STS
+	for _, ctx := range []context.Context{ctx, ctx, withClientPool(ctx, NewClientPool()), withClientPool(ctx, NewClientPool())} {
+		if _, err := newARNBuilder(cfg).Account(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls := server.CallCount(fakeaws.OpGetCallerIdentity); calls != 4 {
+		t.Errorf("expected 4 GetCallerIdentity calls, got %d", calls)
+	}
+}
+
+func TestValidateARNTemplate(t *testing.T) {
+	if err := validateARNTemplate("arn:${partition}:sqs:${region}:${account}:${id}"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateARNTemplate("arn:${partition}:iam::${acount}:role/${id}"); err == nil || !strings.Contains(err.Error(), "${acount}") {
+		t.Errorf("expected the misspelled placeholder to be reported, got %v", err)
+	}
+}
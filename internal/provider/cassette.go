@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/shakefu/terraform-provider-probe/internal/cassette"
+)
+
+// Environment variables that route the provider's AWS traffic through a
+// cassette during acceptance tests.
+const (
+	// EnvCassette is the path of the cassette file.
+	EnvCassette = "PROBE_CASSETTE"
+
+	// EnvCassetteMode is "record" to capture real responses, or "replay"
+	// (the default) to answer requests from the cassette.
+	EnvCassetteMode = "PROBE_CASSETTE_MODE"
+)
+
+// useCassette installs a recording or replaying transport on cfg when
+// running under acceptance tests (TF_ACC is set) and EnvCassette names a
+// file. Outside of tests the environment variables are ignored.
+func useCassette(cfg *aws.Config) error {
+	path := os.Getenv(EnvCassette)
+	if path == "" || os.Getenv("TF_ACC") == "" {
+		return nil
+	}
+
+	switch mode := os.Getenv(EnvCassetteMode); mode {
+	case "record":
+		cfg.HTTPClient = &http.Client{Transport: cassette.NewRecorder(path, nil)}
+	case "", "replay":
+		replayer, err := cassette.LoadReplayer(path)
+		if err != nil {
+			return err
+		}
+		cfg.HTTPClient = &http.Client{Transport: replayer}
+		// Replayed requests are never sent, so real credentials aren't needed.
+		cfg.Credentials = credentials.NewStaticCredentialsProvider("test", "test", "")
+	default:
+		return fmt.Errorf("%s must be record or replay, got %q", EnvCassetteMode, mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ClientPool shares AWS service clients between the data sources and
+// resources of a configured provider. Clients are safe for concurrent use,
+// so the pool builds one per service, region and credentials. It also caches
+// the account each set of credentials belongs to.
+type ClientPool struct {
+	mu       sync.Mutex
+	clients  map[clientKey]any
+	accounts map[accountKey]*accountEntry
+}
+
+// clientKey identifies a pooled client.
+type clientKey struct {
+	service     string
+	region      string
+	credentials string
+}
+
+// NewClientPool creates an empty pool.
+func NewClientPool() *ClientPool {
+	return &ClientPool{
+		clients:  make(map[clientKey]any),
+		accounts: make(map[accountKey]*accountEntry),
+	}
+}
+
+// clientPoolKey is the context key of a ClientPool.
+type clientPoolKey struct{}
+
+// withClientPool returns a context carrying pool, so probers built without
+// access to the provider can share its clients and caches.
+func withClientPool(ctx context.Context, pool *ClientPool) context.Context {
+	if pool == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, clientPoolKey{}, pool)
+}
+
+// clientPoolFrom returns the pool ctx carries, or nil.
+func clientPoolFrom(ctx context.Context) *ClientPool {
+	pool, _ := ctx.Value(clientPoolKey{}).(*ClientPool)
+	return pool
+}
+
+// pooledClient returns pool's client for service in cfg's region and
+// credentials, building it with build the first time. A nil pool builds a
+// new client on every call.
+func pooledClient[T any](pool *ClientPool, service string, cfg aws.Config, build func(aws.Config) T) T {
+	if pool == nil {
+		return build(cfg)
+	}
+
+	key := clientKey{
+		service:     service,
+		region:      cfg.Region,
+		credentials: credentialsKey(cfg.Credentials),
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if client, ok := pool.clients[key].(T); ok {
+		return client
+	}
+	client := build(cfg)
+	pool.clients[key] = client
+	return client
+}
+
+// credentialsKey identifies a credentials provider without retrieving
+// credentials. Providers held by reference, like the cache LoadDefaultConfig
+// returns, are identified by address; values such as static credentials by a
+// digest of their contents, so secrets never appear in the key.
+func credentialsKey(credentials aws.CredentialsProvider) string {
+	if credentials == nil {
+		return "anonymous"
+	}
+
+	v := reflect.ValueOf(credentials)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Func, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return fmt.Sprintf("%T@%x", credentials, v.Pointer())
+	}
+
+	sum := sha256.Sum256(fmt.Appendf(nil, "%#v", credentials))
+	return fmt.Sprintf("%T#%x", credentials, sum[:8])
+}
@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// testClient stands in for an AWS service client.
+type testClient struct {
+	region string
+}
+
+func TestPooledClient(t *testing.T) {
+	var builds atomic.Int32
+	build := func(cfg aws.Config) *testClient {
+		builds.Add(1)
+		return &testClient{region: cfg.Region}
+	}
+
+	cache := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""))
+	east := aws.Config{Region: "us-east-1", Credentials: cache}
+	pool := NewClientPool()
+
+	first := pooledClient(pool, "iam", east, build)
+	if again := pooledClient(pool, "iam", east, build); again != first {
+		t.Error("expected the same client for the same service, region and credentials")
+	}
+
+	west := east.Copy()
+	west.Region = "us-west-2"
+	other := east.Copy()
+	other.Credentials = aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""))
+	distinct := []struct {
+		name    string
+		service string
+		cfg     aws.Config
+	}{
+		{name: "service", service: "sts", cfg: east},
+		{name: "region", service: "iam", cfg: west},
+		{name: "credentials", service: "iam", cfg: other},
+	}
+	for _, tt := range distinct {
+		t.Run(tt.name, func(t *testing.T) {
+			if client := pooledClient(pool, tt.service, tt.cfg, build); client == first {
+				t.Errorf("expected a different %s to get its own client", tt.name)
+			}
+		})
+	}
+	if got := builds.Load(); got != 4 {
+		t.Errorf("built %d clients, want 4", got)
+	}
+
+	t.Run("nil pool", func(t *testing.T) {
+		var pool *ClientPool
+		if pooledClient(pool, "iam", east, build) == pooledClient(pool, "iam", east, build) {
+			t.Error("expected a nil pool to build a client per call")
+		}
+	})
+}
+
+func TestPooledClient_Concurrent(t *testing.T) {
+	var builds atomic.Int32
+	build := func(cfg aws.Config) *testClient {
+		builds.Add(1)
+		return &testClient{region: cfg.Region}
+	}
+	pool := NewClientPool()
+	regions := []string{"us-east-1", "eu-west-1"}
+
+	var wg sync.WaitGroup
+	for i := range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			region := regions[i%len(regions)]
+			if client := pooledClient(pool, "iam", aws.Config{Region: region}, build); client.region != region {
+				t.Errorf("got a client for %s, want %s", client.region, region)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := builds.Load(); got != int32(len(regions)) {
+		t.Errorf("built %d clients, want one per region (%d)", got, len(regions))
+	}
+}
+
+func TestCredentialsKey(t *testing.T) {
+	static := credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")
+	cache := aws.NewCredentialsCache(static)
+
+	if credentialsKey(nil) != "anonymous" {
+		t.Errorf("credentialsKey(nil) = %q, want anonymous", credentialsKey(nil))
+	}
+	if credentialsKey(cache) != credentialsKey(cache) {
+		t.Error("expected a stable key for the same provider")
+	}
+	if credentialsKey(cache) == credentialsKey(aws.NewCredentialsCache(static)) {
+		t.Error("expected distinct caches to get distinct keys")
+	}
+	if credentialsKey(static) != credentialsKey(credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")) {
+		t.Error("expected equal static credentials to share a key")
+	}
+	if credentialsKey(static) == credentialsKey(credentials.NewStaticCredentialsProvider("AKID", "OTHER", "")) {
+		t.Error("expected different static credentials to get distinct keys")
+	}
+	if key := credentialsKey(static); strings.Contains(key, "SECRET") {
+		t.Errorf("credentialsKey leaked the secret: %q", key)
+	}
+}
+
+func TestProbeDataSource_ConcurrentReads(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	for i := range 4 {
+		server.PutTable(fakeaws.Table{Name: fmt.Sprintf("table-%d", i)})
+		server.PutBucket(fmt.Sprintf("bucket-%d", i), fakeaws.Bucket{})
+	}
+
+	// One data source instance serves every data "probe" block.
+	d := &ProbeDataSource{registry: probe.NewProberRegistry(cfg)}
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			typeName, id := "aws_dynamodb_table", fmt.Sprintf("table-%d", i%4)
+			if i%2 == 1 {
+				typeName, id = "aws_s3_bucket", fmt.Sprintf("bucket-%d", i%4)
+			}
+			data, diags := readProbe(t, d, map[string]tftypes.Value{
+				"type": tftypes.NewValue(tftypes.String, typeName),
+				"id":   tftypes.NewValue(tftypes.String, id),
+			})
+			if diags.HasError() {
+				t.Errorf("%s %s: unexpected diagnostics: %v", typeName, id, diags)
+				return
+			}
+			if !data.Exists.ValueBool() {
+				t.Errorf("%s %s: expected the resource to exist", typeName, id)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// desiredProperties converts the desired argument of the probe data source
+// to Go values. It returns nil if desired is null.
+func desiredProperties(ctx context.Context, desired types.Dynamic) (map[string]any, error) {
+	if desired.IsNull() || desired.IsUnderlyingValueNull() {
+		return nil, nil
+	}
+
+	value, err := desired.UnderlyingValue().ToTerraformValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tftypesToGo(value)
+	if err != nil {
+		return nil, err
+	}
+	props, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("desired must be an object of property values, got %s", value.Type())
+	}
+	return props, nil
+}
+
+// checkDesired returns the names of desired properties that aren't among
+// immutable, sorted.
+func checkDesired(desired map[string]any, immutable []probe.ImmutableProperty) []string {
+	known := make(map[string]bool, len(immutable))
+	for _, property := range immutable {
+		known[property.Name] = true
+	}
+
+	var unknown []string
+	for name := range desired {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// immutableNames lists the names of immutable properties for diagnostics.
+func immutableNames(immutable []probe.ImmutableProperty) string {
+	if len(immutable) == 0 {
+		return "none"
+	}
+	names := make([]string, len(immutable))
+	for i, property := range immutable {
+		names[i] = property.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// conflictingProperties returns the immutable properties whose desired value
+// differs from what the probe reported, in the order immutable lists them.
+// A desired property the probe didn't report conflicts, since it can't be
+// verified.
+func conflictingProperties(desired, actual map[string]any, immutable []probe.ImmutableProperty) []string {
+	conflicts := []string{}
+	for _, property := range immutable {
+		want, ok := desired[property.Name]
+		if !ok {
+			continue
+		}
+		got, ok := actual[property.Name]
+		if !ok || !sameValue(want, got, property.Unordered) {
+			conflicts = append(conflicts, property.Name)
+		}
+	}
+	return conflicts
+}
+
+// sameValue compares values by their JSON form, so SDK structs compare
+// equal to the objects a configuration declares.
+func sameValue(want, got any, unordered bool) bool {
+	a, errA := normalizeJSON(want)
+	b, errB := normalizeJSON(got)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	if unordered {
+		listA, okA := a.([]any)
+		listB, okB := b.([]any)
+		if okA && okB {
+			return sameElements(listA, listB)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// sameElements reports whether two lists hold the same elements in any
+// order.
+func sameElements(a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	key := func(v any) string {
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+	counts := make(map[string]int, len(a))
+	for _, v := range a {
+		counts[key(v)]++
+	}
+	for _, v := range b {
+		k := key(v)
+		if counts[k] == 0 {
+			return false
+		}
+		counts[k]--
+	}
+	return true
+}
+
+// normalizeJSON round-trips v through JSON.
+func normalizeJSON(v any) (any, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	err = json.Unmarshal(encoded, &normalized)
+	return normalized, err
+}
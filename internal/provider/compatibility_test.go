@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+func TestSameValue(t *testing.T) {
+	a := []any{map[string]any{"AttributeName": "id"}, map[string]any{"AttributeName": "sk"}}
+	b := []map[string]string{{"AttributeName": "sk"}, {"AttributeName": "id"}}
+
+	if sameValue(a, b, false) {
+		t.Error("expected reordered lists to differ when ordered")
+	}
+	if !sameValue(a, b, true) {
+		t.Error("expected reordered lists to match when unordered")
+	}
+	if sameValue(a, b[:1], true) {
+		t.Error("expected lists of different lengths to differ")
+	}
+	if !sameValue("us-east-1", "us-east-1", false) || sameValue("us-east-1", "eu-west-1", false) {
+		t.Error("unexpected scalar comparison")
+	}
+}
+
+func TestProbeDataSource_Desired(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutTable(fakeaws.Table{Name: "orders", HashKey: "order_id"})
+	server.PutBucket("assets", fakeaws.Bucket{Region: "eu-west-1"})
+
+	d := &ProbeDataSource{cfg: cfg, registry: probe.NewProberRegistry(cfg)}
+
+	keyType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"AttributeName": tftypes.String,
+		"KeyType":       tftypes.String,
+	}}
+	keySchema := func(name string) tftypes.Value {
+		return tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{keyType}}, []tftypes.Value{
+			tftypes.NewValue(keyType, map[string]tftypes.Value{
+				"AttributeName": tftypes.NewValue(tftypes.String, name),
+				"KeyType":       tftypes.NewValue(tftypes.String, "HASH"),
+			}),
+		})
+	}
+	desired := func(name string, value tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{name: value.Type()}},
+			map[string]tftypes.Value{name: value})
+	}
+	probeValues := func(resourceType, id string, desired tftypes.Value) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"type":    tftypes.NewValue(tftypes.String, resourceType),
+			"id":      tftypes.NewValue(tftypes.String, id),
+			"desired": desired,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		values     map[string]tftypes.Value
+		compatible bool
+		conflicts  []string
+	}{
+		{
+			name:       "matching key schema",
+			values:     probeValues("aws_dynamodb_table", "orders", desired("KeySchema", keySchema("order_id"))),
+			compatible: true,
+		},
+		{
+			name:      "different key schema",
+			values:    probeValues("aws_dynamodb_table", "orders", desired("KeySchema", keySchema("id"))),
+			conflicts: []string{"KeySchema"},
+		},
+		{
+			name:       "missing table",
+			values:     probeValues("aws_dynamodb_table", "invoices", desired("KeySchema", keySchema("id"))),
+			compatible: true,
+		},
+		{
+			name:      "different bucket region",
+			values:    probeValues("aws_s3_bucket", "assets", desired("Region", tftypes.NewValue(tftypes.String, "us-east-1"))),
+			conflicts: []string{"Region"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, diags := readProbe(t, d, tt.values)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if model.Compatible.ValueBool() != tt.compatible {
+				t.Errorf("expected compatible=%t, got %v", tt.compatible, model.Compatible)
+			}
+			var conflicts []string
+			model.Conflicts.ElementsAs(t.Context(), &conflicts, false)
+			if len(conflicts) != len(tt.conflicts) || (len(conflicts) > 0 && conflicts[0] != tt.conflicts[0]) {
+				t.Errorf("expected conflicts %v, got %v", tt.conflicts, conflicts)
+			}
+		})
+	}
+
+	t.Run("no desired", func(t *testing.T) {
+		model, diags := readProbe(t, d, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+			"id":   tftypes.NewValue(tftypes.String, "orders"),
+		})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !model.Compatible.IsNull() || !model.Conflicts.IsNull() {
+			t.Errorf("expected null compatibility, got %v and %v", model.Compatible, model.Conflicts)
+		}
+	})
+
+	t.Run("mutable property", func(t *testing.T) {
+		_, diags := readProbe(t, d, probeValues("aws_dynamodb_table", "orders",
+			desired("DeletionProtectionEnabled", tftypes.NewValue(tftypes.Bool, true))))
+		if !diags.HasError() || diags[0].Summary() != "Invalid desired properties" {
+			t.Fatalf("expected a mutable property to be rejected, got %v", diags)
+		}
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		_, diags := readProbe(t, d, probeValues("aws_dynamodb_table", "orders", tftypes.NewValue(tftypes.String, "KeySchema")))
+		if !diags.HasError() || diags[0].Summary() != "Invalid desired properties" {
+			t.Fatalf("expected a non-object to be rejected, got %v", diags)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/probe"
+	"github.com/shakefu/terraform-provider-probe/probe/probetest"
+)
+
+// conformanceBackends maps every prober type to the scripted backend its
+// conformance run uses. A prober registered without a backend fails
+// TestProberConformance.
+var conformanceBackends = map[string]func(t *testing.T) probetest.Backend{
+	"aws_dynamodb_table": func(t *testing.T) probetest.Backend {
+		return newFakeAWSBackend(t, func(s *fakeaws.Server, id string, tags map[string]string) {
+			s.PutTable(fakeaws.Table{Name: id, Tags: tags})
+		}, fakeaws.OpDescribeTable, fakeaws.OpListTagsOfResource)
+	},
+	"aws_s3_bucket": func(t *testing.T) probetest.Backend {
+		return newFakeAWSBackend(t, func(s *fakeaws.Server, id string, tags map[string]string) {
+			s.PutBucket(id, fakeaws.Bucket{Tags: tags})
+		}, fakeaws.OpHeadBucket, fakeaws.OpGetBucketLocation, fakeaws.OpGetBucketTagging)
+	},
+	"aws_iam_role": newIamRoleBackend,
+}
+
+// TestProberConformance runs the probetest suite against every registered
+// prober, plus a declarative prober.
+func TestProberConformance(t *testing.T) {
+	catalog := probe.DefaultCatalog().Clone()
+	RegisterDefinitions(catalog, []ProberDefinition{testIamRoleDefinition()})
+
+	for _, typ := range catalog.Types() {
+		newBackend, ok := conformanceBackends[typ]
+		if !ok {
+			t.Errorf("no conformance backend for %s; add one to conformanceBackends", typ)
+			continue
+		}
+		factory, _ := catalog.Factory(typ)
+
+		t.Run(typ, func(t *testing.T) {
+			probetest.Run(t, factory, newBackend, probetest.Options{
+				CheckProperties: func(properties map[string]any) error {
+					_, diags := convertMapToDynamic(properties)
+					if diags.HasError() {
+						return fmt.Errorf("%v", diags)
+					}
+					return nil
+				},
+			})
+		})
+	}
+}
+
+// fakeAWSBackend adapts a fakeaws.Server to probetest.Backend.
+type fakeAWSBackend struct {
+	server     *fakeaws.Server
+	cfg        aws.Config
+	create     func(s *fakeaws.Server, id string, tags map[string]string)
+	operations []string
+}
+
+func newFakeAWSBackend(t *testing.T, create func(*fakeaws.Server, string, map[string]string), operations ...string) *fakeAWSBackend {
+	server, cfg := getFakeAWSConfig(t)
+	return &fakeAWSBackend{server: server, cfg: cfg, create: create, operations: operations}
+}
+
+func (b *fakeAWSBackend) Config() aws.Config {
+	return b.cfg
+}
+
+func (b *fakeAWSBackend) Create(t *testing.T, identifier string, tags map[string]string) {
+	b.create(b.server, identifier, tags)
+}
+
+func (b *fakeAWSBackend) Fail(t *testing.T, status int, code string) {
+	for _, op := range b.operations {
+		b.server.Fail(op, fakeaws.Error{Status: status, Code: code, Message: "scripted failure"})
+	}
+}
+
+// iamRoleBackend serves GetRole for the declarative IAM role prober.
+type iamRoleBackend struct {
+	server *httptest.Server
+
+	mu    sync.Mutex
+	roles map[string]map[string]string
+	fail  *fakeaws.Error
+}
+
+func newIamRoleBackend(t *testing.T) probetest.Backend {
+	b := &iamRoleBackend{roles: make(map[string]map[string]string)}
+	b.server = httptest.NewServer(http.HandlerFunc(b.serveHTTP))
+	t.Cleanup(b.server.Close)
+	return b
+}
+
+func (b *iamRoleBackend) Config() aws.Config {
+	cfg := testDeclarativeConfig(b.server)
+	cfg.Retryer = func() aws.Retryer {
+		return retry.NewStandard(func(o *retry.StandardOptions) {
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) {
+				return 0, nil
+			})
+		})
+	}
+	return cfg
+}
+
+func (b *iamRoleBackend) Create(t *testing.T, identifier string, tags map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roles[identifier] = tags
+}
+
+func (b *iamRoleBackend) Fail(t *testing.T, status int, code string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fail = &fakeaws.Error{Status: status, Code: code, Message: "scripted failure"}
+}
+
+func (b *iamRoleBackend) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	name := r.Form.Get("RoleName")
+
+	b.mu.Lock()
+	fail := b.fail
+	tags, ok := b.roles[name]
+	b.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/xml")
+	switch {
+	case fail != nil:
+		w.WriteHeader(fail.Status)
+		fmt.Fprintf(w, `<ErrorResponse><Error><Code>%s</Code><Message>%s</Message></Error></ErrorResponse>`, fail.Code, fail.Message)
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(testNoSuchEntityResponse))
+	default:
+		keys := make([]string, 0, len(tags))
+		for key := range tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var members string
+		for _, key := range keys {
+			members += fmt.Sprintf("<member><Key>%s</Key><Value>%s</Value></member>", html.EscapeString(key), html.EscapeString(tags[key]))
+		}
+		fmt.Fprintf(w, `<GetRoleResponse><GetRoleResult><Role><RoleName>%[1]s</RoleName><Arn>arn:aws:iam::123456789012:role/%[1]s</Arn><Tags>%[2]s</Tags></Role></GetRoleResult></GetRoleResponse>`, html.EscapeString(name), members)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// unknownAttributes returns the names of the top-level attributes and blocks
+// of config whose values aren't fully known, sorted.
+func unknownAttributes(config tftypes.Value) []string {
+	if config.IsFullyKnown() {
+		return nil
+	}
+
+	var attributes map[string]tftypes.Value
+	if err := config.As(&attributes); err != nil {
+		// The whole configuration is unknown
+		return []string{"(all)"}
+	}
+
+	var unknown []string
+	for name, v := range attributes {
+		if !v.IsFullyKnown() {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// providerConfigUnknown reports that a probe can't run because the provider
+// configuration depends on values known only after apply, and Terraform
+// didn't offer to defer it.
+func providerConfigUnknown(attributes []string) diag.Diagnostic {
+	verb := "is"
+	if len(attributes) > 1 {
+		verb = "are"
+	}
+	return diag.NewErrorDiagnostic(
+		"Provider Configuration Unknown",
+		fmt.Sprintf("The provider's %s %s not known until apply, so it can't call AWS during this plan. "+
+			"Enable deferred actions so Terraform plans the probe once the configuration is known, "+
+			"or apply the resources the provider configuration depends on first.",
+			strings.Join(attributes, ", "), verb),
+	)
+}
+
+// configUnknown reports that a data source can't be read because its own
+// configuration depends on values known only after apply, and Terraform
+// didn't offer to defer it.
+func configUnknown(attributes []string) diag.Diagnostic {
+	verb := "is"
+	if len(attributes) > 1 {
+		verb = "are"
+	}
+	return diag.NewErrorDiagnostic(
+		"Configuration Unknown",
+		fmt.Sprintf("The data source's %s %s not known until apply, so it can't be read during this plan. "+
+			"Enable deferred actions so Terraform reads it once the configuration is known, "+
+			"or apply the resources its configuration depends on first.",
+			strings.Join(attributes, ", "), verb),
+	)
+}
+
+// deferRead answers a data source read whose configuration isn't fully
+// known. When the client supports deferral, every attribute is unknown and
+// the read is deferred; otherwise the read fails and the state is left alone.
+// Terraform usually postpones such reads to apply on its own, so this guards
+// against clients that send them anyway.
+func deferRead(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if !req.ClientCapabilities.DeferralAllowed {
+		resp.Diagnostics.Append(configUnknown(unknownAttributes(req.Config.Raw)))
+		return
+	}
+	resp.State.Raw = tftypes.NewValue(req.Config.Schema.Type().TerraformType(ctx), tftypes.UnknownValue)
+	resp.Deferred = &datasource.Deferred{Reason: datasource.DeferredReasonDataSourceConfigUnknown}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestUnknownAttributes(t *testing.T) {
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"region":   tftypes.String,
+		"endpoint": tftypes.String,
+		"tags":     tftypes.Map{ElementType: tftypes.String},
+	}}
+	value := func(region, endpoint any, tags tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{
+			"region":   tftypes.NewValue(tftypes.String, region),
+			"endpoint": tftypes.NewValue(tftypes.String, endpoint),
+			"tags":     tags,
+		})
+	}
+	knownTags := tftypes.NewValue(objType.AttributeTypes["tags"], nil)
+	partialTags := tftypes.NewValue(objType.AttributeTypes["tags"], map[string]tftypes.Value{
+		"Team": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+
+	tests := []struct {
+		name     string
+		config   tftypes.Value
+		expected []string
+	}{
+		{name: "known", config: value("us-east-1", nil, knownTags)},
+		{name: "unknown attributes", config: value(tftypes.UnknownValue, tftypes.UnknownValue, knownTags), expected: []string{"endpoint", "region"}},
+		{name: "partially known", config: value("us-east-1", nil, partialTags), expected: []string{"tags"}},
+		{name: "wholly unknown", config: tftypes.NewValue(objType, tftypes.UnknownValue), expected: []string{"(all)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unknownAttributes(tt.config); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("unknownAttributes() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProbeProvider_ConfigureUnknown(t *testing.T) {
+	ctx := context.Background()
+	p := New("test")().(*ProbeProvider)
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, map[string]tftypes.Value{
+		"region": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})}
+
+	t.Run("deferral allowed", func(t *testing.T) {
+		req := provider.ConfigureRequest{Config: config}
+		req.ClientCapabilities.DeferralAllowed = true
+		var resp provider.ConfigureResponse
+		p.Configure(ctx, req, &resp)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		if resp.Deferred == nil || resp.Deferred.Reason != provider.DeferredReasonProviderConfigUnknown {
+			t.Errorf("expected a deferred response, got %+v", resp.Deferred)
+		}
+	})
+
+	t.Run("deferral not allowed", func(t *testing.T) {
+		var resp provider.ConfigureResponse
+		p.Configure(ctx, provider.ConfigureRequest{Config: config}, &resp)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		providerData, ok := resp.DataSourceData.(*ProbeProviderData)
+		if !ok || !reflect.DeepEqual(providerData.UnknownConfig, []string{"region"}) {
+			t.Fatalf("expected the unknown region to be recorded, got %+v", resp.DataSourceData)
+		}
+
+		d := NewProbeDataSource().(*ProbeDataSource)
+		d.Configure(ctx, datasource.ConfigureRequest{ProviderData: providerData}, &datasource.ConfigureResponse{})
+		_, diags := readProbe(t, d, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "aws_s3_bucket"),
+			"id":   tftypes.NewValue(tftypes.String, "assets"),
+		})
+		if !diags.HasError() || diags[0].Summary() != "Provider Configuration Unknown" {
+			t.Fatalf("expected the unknown configuration to be reported, got %v", diags)
+		}
+
+		r := NewProbeAdoptionResource().(*ProbeAdoptionResource)
+		r.Configure(ctx, resource.ConfigureRequest{ProviderData: providerData}, &resource.ConfigureResponse{})
+		planned, _, diags := planAdoption(t, r, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "aws_s3_bucket"),
+			"id":   tftypes.NewValue(tftypes.String, "assets"),
+		})
+		if !planned.PreExisted.IsUnknown() {
+			t.Errorf("expected pre_existed to stay unknown, got %v (%v)", planned.PreExisted, diags)
+		}
+	})
+}
+
+func TestDeferRead(t *testing.T) {
+	ctx := context.Background()
+
+	read := func(t *testing.T, d datasource.DataSource, values map[string]tftypes.Value, deferralAllowed bool) (datasource.ReadResponse, tftypes.Value) {
+		t.Helper()
+
+		var schemaResp datasource.SchemaResponse
+		d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+		objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+		req := datasource.ReadRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, values)},
+		}
+		req.ClientCapabilities.DeferralAllowed = deferralAllowed
+		resp := datasource.ReadResponse{
+			State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, nil)},
+		}
+		d.Read(ctx, req, &resp)
+		return resp, tftypes.NewValue(objType, nil)
+	}
+	deferred := func(t *testing.T, resp datasource.ReadResponse) {
+		t.Helper()
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		if resp.State.Raw.IsKnown() {
+			t.Errorf("expected unknown state, got %v", resp.State.Raw)
+		}
+	}
+
+	probeValues := map[string]tftypes.Value{
+		"type": tftypes.NewValue(tftypes.String, "aws_s3_bucket"),
+		"id":   tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	}
+	simulationValues := map[string]tftypes.Value{
+		"policy_source_arn": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"actions":           tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "s3:GetObject")}),
+	}
+
+	resp, _ := read(t, &ProbeDataSource{}, probeValues, true)
+	deferred(t, resp)
+	if resp.Deferred == nil || resp.Deferred.Reason != datasource.DeferredReasonDataSourceConfigUnknown {
+		t.Errorf("expected the probe to be deferred, got %+v", resp.Deferred)
+	}
+
+	resp, _ = read(t, &IamPolicySimulationDataSource{}, simulationValues, true)
+	deferred(t, resp)
+	if resp.Deferred == nil {
+		t.Error("expected the simulation to be deferred")
+	}
+
+	resp, prior := read(t, &ProbeDataSource{}, probeValues, false)
+	if resp.Deferred != nil {
+		t.Errorf("expected no deferral without client support, got %+v", resp.Deferred)
+	}
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Configuration Unknown" {
+		t.Fatalf("expected the unknown configuration to be reported, got %v", resp.Diagnostics)
+	}
+	if !strings.Contains(resp.Diagnostics[0].Detail(), "id is not known") {
+		t.Errorf("expected the unknown attribute to be named, got %q", resp.Diagnostics[0].Detail())
+	}
+	if !resp.State.Raw.Equal(prior) {
+		t.Errorf("expected the state to be left alone, got %v", resp.State.Raw)
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Emulators accepted by the emulator provider setting.
+const (
+	EmulatorLocalStack    = "localstack"
+	EmulatorMoto          = "moto"
+	EmulatorMinIO         = "minio"
+	EmulatorDynamoDBLocal = "dynamodb_local"
+)
+
+// healthTimeout bounds each emulator health check.
+const healthTimeout = 500 * time.Millisecond
+
+// EmulatorInfo describes the local emulator the provider talks to.
+type EmulatorInfo struct {
+	// Name is one of the Emulator constants.
+	Name string
+
+	// Endpoint is the base URL requests are sent to.
+	Endpoint string
+
+	// Edition and Version are reported by emulators whose health endpoint
+	// includes them.
+	Edition string
+	Version string
+
+	// Services maps service names to their status, such as "available",
+	// "running" or "disabled". Nil if unknown.
+	Services map[string]string
+}
+
+// CheckService returns an error diagnostic if the emulator reports service
+// as disabled or failed, or doesn't implement it at all. It is safe to call
+// on a nil receiver.
+func (i *EmulatorInfo) CheckService(service string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if i == nil {
+		return diags
+	}
+
+	title := emulatorTitle(i.Name)
+	status, listed := i.Services[strings.ToLower(service)]
+	switch {
+	case listed && (status == "disabled" || status == "error"):
+		diags.AddError(
+			"Service not available in "+title,
+			fmt.Sprintf("%s at %s reports the %q service as %s. Enable it (for LocalStack, by adding it to the SERVICES variable) or point the provider at an endpoint that serves it.",
+				title, i.Endpoint, service, status),
+		)
+	case !listed && emulatorProfiles[i.Name].services != nil:
+		diags.AddError(
+			"Service not available in "+title,
+			fmt.Sprintf("%s at %s doesn't implement the %q service. Probe it against AWS or an emulator that does.",
+				title, i.Endpoint, service),
+		)
+	}
+	return diags
+}
+
+// emulatorProfile describes how to find and talk to an emulator.
+type emulatorProfile struct {
+	// title names the emulator in diagnostics.
+	title string
+
+	// endpoint returns where the emulator is expected when the provider
+	// has no endpoint configured.
+	endpoint func() string
+
+	// health checks the emulator at endpoint.
+	health func(ctx context.Context, endpoint string) (*EmulatorInfo, error)
+
+	// credentials returns the credentials the emulator requires. Nil means
+	// it accepts any, so configured credentials are used if there are any.
+	credentials func() aws.CredentialsProvider
+
+	// services lists the services the emulator implements. Nil means it
+	// implements many, and unlisted services are assumed to work.
+	services []string
+
+	// unimplemented maps SDK service IDs to operations the emulator lacks.
+	// Calls to them fail before a request is sent.
+	unimplemented map[string][]string
+}
+
+var emulatorProfiles = map[string]emulatorProfile{
+	EmulatorLocalStack: {
+		title:    "LocalStack",
+		endpoint: localStackEndpoint,
+		health:   fetchLocalStackHealth,
+	},
+	EmulatorMoto: {
+		title:    "moto",
+		endpoint: envEndpoint("http://localhost:5000"),
+		health:   statusHealth("/moto-api/", false),
+	},
+	EmulatorMinIO: {
+		title:       "MinIO",
+		endpoint:    envEndpoint("http://localhost:9000"),
+		health:      statusHealth("/minio/health/live", false),
+		credentials: minioCredentials,
+		services:    []string{"s3"},
+	},
+	EmulatorDynamoDBLocal: {
+		title:    "DynamoDB Local",
+		endpoint: envEndpoint("http://localhost:8000"),
+		// DynamoDB Local has no health endpoint; any HTTP answer will do.
+		health:   statusHealth("/", true),
+		services: []string{"dynamodb"},
+		unimplemented: map[string][]string{
+			"DynamoDB": {"ListTagsOfResource"},
+		},
+	},
+}
+
+// EmulatorNames returns the accepted emulator setting values, sorted.
+func EmulatorNames() []string {
+	names := make([]string, 0, len(emulatorProfiles))
+	for name := range emulatorProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// emulatorTitle returns the display name of an emulator.
+func emulatorTitle(name string) string {
+	if profile, ok := emulatorProfiles[name]; ok {
+		return profile.title
+	}
+	return "emulator"
+}
+
+// validateEmulatorSettings checks the emulator setting and its interaction
+// with the localstack setting.
+func validateEmulatorSettings(settings AWSSettings) error {
+	if settings.Emulator == "" {
+		return nil
+	}
+	if _, ok := emulatorProfiles[settings.Emulator]; !ok {
+		return fmt.Errorf("unknown emulator %q; valid values are %s", settings.Emulator, strings.Join(EmulatorNames(), ", "))
+	}
+	if settings.LocalStack != nil && (!*settings.LocalStack || settings.Emulator != EmulatorLocalStack) {
+		return fmt.Errorf("localstack = %t conflicts with emulator = %q; set only emulator", *settings.LocalStack, settings.Emulator)
+	}
+	return nil
+}
+
+// resolveEmulator returns the emulator settings select, or nil for AWS.
+// Without an emulator setting, an endpoint or localstack = true means
+// LocalStack, and otherwise LocalStack is auto-detected.
+func resolveEmulator(ctx context.Context, settings AWSSettings) (*EmulatorInfo, error) {
+	if err := validateEmulatorSettings(settings); err != nil {
+		return nil, err
+	}
+
+	name := settings.Emulator
+	if name == "" {
+		switch {
+		case settings.Endpoint != "":
+			name = EmulatorLocalStack
+		case settings.LocalStack != nil:
+			if !*settings.LocalStack {
+				return nil, nil
+			}
+			name = EmulatorLocalStack
+		default:
+			return detectLocalStack(ctx), nil
+		}
+	}
+
+	profile := emulatorProfiles[name]
+	endpoint := settings.Endpoint
+	if endpoint == "" {
+		endpoint = profile.endpoint()
+	}
+
+	info, err := profile.health(ctx, endpoint)
+	if err != nil {
+		// The user chose this emulator, so use it even if it looks down;
+		// requests will report the real problem.
+		tflog.Debug(ctx, "Emulator health unavailable", map[string]any{
+			"emulator": name,
+			"endpoint": endpoint,
+			"error":    err.Error(),
+		})
+		info = &EmulatorInfo{Endpoint: endpoint}
+	}
+	info.Name = name
+	if info.Services == nil && profile.services != nil {
+		info.Services = make(map[string]string, len(profile.services))
+		for _, service := range profile.services {
+			info.Services[service] = "available"
+		}
+	}
+
+	return info, nil
+}
+
+// configureEmulator points cfg at the emulator and applies its credential
+// and compatibility quirks.
+func configureEmulator(ctx context.Context, cfg *aws.Config, emulator *EmulatorInfo) {
+	profile := emulatorProfiles[emulator.Name]
+
+	cfg.BaseEndpoint = aws.String(emulator.Endpoint)
+
+	if profile.credentials != nil {
+		cfg.Credentials = profile.credentials()
+	} else if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		// Use dummy credentials if none are configured
+		cfg.Credentials = credentials.NewStaticCredentialsProvider("test", "test", "")
+	}
+
+	if len(profile.unimplemented) > 0 {
+		cfg.APIOptions = append(cfg.APIOptions, failUnimplemented(profile.title, profile.unimplemented))
+	}
+}
+
+// failUnimplemented fails calls to operations an emulator lacks before they
+// are sent, so they neither hang nor burn retries.
+func failUnimplemented(title string, unimplemented map[string][]string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ProbeEmulatorUnimplemented",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				service := awsmiddleware.GetServiceID(ctx)
+				operation := awsmiddleware.GetOperationName(ctx)
+				if slices.Contains(unimplemented[service], operation) {
+					return middleware.InitializeOutput{}, middleware.Metadata{}, &smithy.GenericAPIError{
+						Code:    "NotImplemented",
+						Message: fmt.Sprintf("%s does not implement %s", title, operation),
+						Fault:   smithy.FaultClient,
+					}
+				}
+				return next.HandleInitialize(ctx, in)
+			}), middleware.After)
+	}
+}
+
+// envEndpoint returns an endpoint function that prefers AWS_ENDPOINT_URL
+// over fallback.
+func envEndpoint(fallback string) func() string {
+	return func() string {
+		if endpoint := os.Getenv(EnvAWSEndpointURL); endpoint != "" {
+			return strings.TrimSuffix(endpoint, "/")
+		}
+		return fallback
+	}
+}
+
+// statusHealth returns a health check that GETs path and expects a 200, or
+// any response if anyStatus is set.
+func statusHealth(path string, anyStatus bool) func(context.Context, string) (*EmulatorInfo, error) {
+	return func(ctx context.Context, endpoint string) (*EmulatorInfo, error) {
+		resp, err := getHealth(ctx, endpoint+path)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+
+		if !anyStatus && resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s: unexpected status %s", path, resp.Status)
+		}
+		return &EmulatorInfo{Endpoint: endpoint}, nil
+	}
+}
+
+// getHealth GETs an emulator health URL with a short timeout.
+func getHealth(ctx context.Context, url string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelOnClose{resp.Body, cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases a request context when its response body is
+// closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// minioCredentials returns MinIO's root credentials: MINIO_ROOT_USER and
+// MINIO_ROOT_PASSWORD if set, otherwise MinIO's defaults.
+func minioCredentials() aws.CredentialsProvider {
+	user, password := os.Getenv("MINIO_ROOT_USER"), os.Getenv("MINIO_ROOT_PASSWORD")
+	if user == "" || password == "" {
+		user, password = "minioadmin", "minioadmin"
+	}
+	return credentials.NewStaticCredentialsProvider(user, password, "")
+}
@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/probe/awsprobe"
+)
+
+func TestValidateEmulatorSettings(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name     string
+		settings AWSSettings
+		wantErr  string
+	}{
+		{name: "unset", settings: AWSSettings{}},
+		{name: "known", settings: AWSSettings{Emulator: EmulatorMinIO}},
+		{name: "unknown", settings: AWSSettings{Emulator: "floci"}, wantErr: `unknown emulator "floci"`},
+		{name: "localstack true with localstack", settings: AWSSettings{Emulator: EmulatorLocalStack, LocalStack: &enabled}},
+		{name: "localstack true with moto", settings: AWSSettings{Emulator: EmulatorMoto, LocalStack: &enabled}, wantErr: "conflicts"},
+		{name: "localstack false", settings: AWSSettings{Emulator: EmulatorLocalStack, LocalStack: &disabled}, wantErr: "conflicts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEmulatorSettings(tt.settings)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestResolveEmulator(t *testing.T) {
+	t.Run("minio health and services", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/minio/health/live" {
+				w.WriteHeader(http.StatusForbidden)
+			}
+		}))
+		defer server.Close()
+		t.Setenv(EnvAWSEndpointURL, server.URL)
+
+		info, err := resolveEmulator(context.Background(), AWSSettings{Emulator: EmulatorMinIO})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Name != EmulatorMinIO || info.Endpoint != server.URL {
+			t.Errorf("unexpected emulator %+v", info)
+		}
+		if info.Services["s3"] != "available" || len(info.Services) != 1 {
+			t.Errorf("expected only s3, got %v", info.Services)
+		}
+	})
+
+	t.Run("default endpoint", func(t *testing.T) {
+		t.Setenv(EnvAWSEndpointURL, "")
+
+		info, err := resolveEmulator(context.Background(), AWSSettings{Emulator: EmulatorDynamoDBLocal})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Endpoint != "http://localhost:8000" {
+			t.Errorf("expected DynamoDB Local's default endpoint, got %q", info.Endpoint)
+		}
+	})
+
+	t.Run("endpoint setting wins", func(t *testing.T) {
+		t.Setenv(EnvAWSEndpointURL, "http://ignored:1")
+
+		info, err := resolveEmulator(context.Background(), AWSSettings{Emulator: EmulatorMoto, Endpoint: "http://127.0.0.1:1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Name != EmulatorMoto || info.Endpoint != "http://127.0.0.1:1" || info.Services != nil {
+			t.Errorf("unexpected emulator %+v", info)
+		}
+	})
+
+	t.Run("localstack disabled", func(t *testing.T) {
+		disabled := false
+		info, err := resolveEmulator(context.Background(), AWSSettings{LocalStack: &disabled})
+		if err != nil || info != nil {
+			t.Fatalf("expected no emulator, got %+v, %v", info, err)
+		}
+	})
+}
+
+func TestConfigureEmulator_Credentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	t.Run("minio uses its root credentials", func(t *testing.T) {
+		t.Setenv("MINIO_ROOT_USER", "")
+
+		cfg, _, err := loadAWSConfig(context.Background(), AWSSettings{Emulator: EmulatorMinIO, Endpoint: "http://127.0.0.1:1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		creds, err := cfg.Credentials.Retrieve(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if creds.AccessKeyID != "minioadmin" {
+			t.Errorf("expected minioadmin, got %q", creds.AccessKeyID)
+		}
+	})
+
+	t.Run("minio root user from environment", func(t *testing.T) {
+		t.Setenv("MINIO_ROOT_USER", "ci")
+		t.Setenv("MINIO_ROOT_PASSWORD", "ci-password")
+
+		cfg, _, err := loadAWSConfig(context.Background(), AWSSettings{Emulator: EmulatorMinIO, Endpoint: "http://127.0.0.1:1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		creds, _ := cfg.Credentials.Retrieve(context.Background())
+		if creds.AccessKeyID != "ci" || creds.SecretAccessKey != "ci-password" {
+			t.Errorf("expected MINIO_ROOT_USER credentials, got %q", creds.AccessKeyID)
+		}
+	})
+
+	t.Run("moto keeps configured credentials", func(t *testing.T) {
+		cfg, _, err := loadAWSConfig(context.Background(), AWSSettings{Emulator: EmulatorMoto, Endpoint: "http://127.0.0.1:1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		creds, _ := cfg.Credentials.Retrieve(context.Background())
+		if creds.AccessKeyID != "AKIDEXAMPLE" {
+			t.Errorf("expected configured credentials, got %q", creds.AccessKeyID)
+		}
+	})
+}
+
+func TestEmulatorInfo_CheckService(t *testing.T) {
+	minio := &EmulatorInfo{Name: EmulatorMinIO, Endpoint: "http://localhost:9000", Services: map[string]string{"s3": "available"}}
+
+	if diags := minio.CheckService("s3"); diags.HasError() {
+		t.Errorf("unexpected diagnostics for s3: %v", diags)
+	}
+
+	diags := minio.CheckService("dynamodb")
+	if !diags.HasError() || diags[0].Summary() != "Service not available in MinIO" {
+		t.Fatalf("expected MinIO service diagnostic, got %v", diags)
+	}
+	if !strings.Contains(diags[0].Detail(), `doesn't implement the "dynamodb" service`) {
+		t.Errorf("unexpected detail %q", diags[0].Detail())
+	}
+
+	moto := &EmulatorInfo{Name: EmulatorMoto}
+	if moto.CheckService("kinesis").HasError() {
+		t.Error("moto should be assumed to implement unlisted services")
+	}
+}
+
+func TestDynamoDBProber_DynamoDBLocal(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	server := fakeaws.New(t)
+	server.PutTable(fakeaws.Table{Name: "orders", Tags: map[string]string{"Environment": "test"}})
+
+	cfg, _, err := loadAWSConfig(context.Background(), AWSSettings{
+		Emulator: EmulatorDynamoDBLocal,
+		Endpoint: server.URL,
+		Region:   fakeaws.Region,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := awsprobe.NewDynamoDBProber(cfg).Probe(context.Background(), "orders")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Exists {
+		t.Fatal("expected Exists to be true")
+	}
+	if result.Tags != nil {
+		t.Errorf("expected no tags from DynamoDB Local, got %v", result.Tags)
+	}
+	if calls := server.CallCount(fakeaws.OpListTagsOfResource); calls != 0 {
+		t.Errorf("expected ListTagsOfResource to be skipped, got %d calls", calls)
+	}
+}
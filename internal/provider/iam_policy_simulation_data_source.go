@@ -26,7 +26,18 @@ var _ datasource.DataSourceWithConfigure = &IamPolicySimulationDataSource{}
 
 // IamPolicySimulationDataSource implements the probe_iam_policy_simulation data source.
 type IamPolicySimulationDataSource struct {
-	cfg aws.Config
+	cfg      aws.Config
+	emulator *EmulatorInfo
+
+	// clients is shared with the provider's other data sources.
+	clients *ClientPool
+
+	// offline is set by overrides_strict, which forbids calls to AWS.
+	offline bool
+
+	// unknownConfig lists the provider arguments that weren't known when
+	// the provider was configured.
+	unknownConfig []string
 }
 
 // IamPolicySimulationDataSourceModel describes the data source data model.
@@ -239,19 +250,36 @@ func (d *IamPolicySimulationDataSource) Configure(ctx context.Context, req datas
 		return
 	}
 
-	cfg, ok := req.ProviderData.(aws.Config)
+	providerData, ok := req.ProviderData.(*ProbeProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected aws.Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ProbeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.cfg = cfg
+	if len(providerData.UnknownConfig) > 0 {
+		d.unknownConfig = providerData.UnknownConfig
+		return
+	}
+
+	d.cfg = providerData.Config
+	d.clients = providerData.Clients
+	d.emulator = providerData.Emulator
+	d.offline = providerData.OverridesStrict
 }
 
 func (d *IamPolicySimulationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if !req.Config.Raw.IsFullyKnown() {
+		deferRead(ctx, req, resp)
+		return
+	}
+	if len(d.unknownConfig) > 0 {
+		resp.Diagnostics.Append(providerConfigUnknown(d.unknownConfig))
+		return
+	}
+
 	var data IamPolicySimulationDataSourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -259,8 +287,22 @@ func (d *IamPolicySimulationDataSource) Read(ctx context.Context, req datasource
 		return
 	}
 
-	// Create IAM client
-	client := iam.NewFromConfig(d.cfg)
+	if d.offline {
+		resp.Diagnostics.AddError(
+			"IAM Policy Simulation Unavailable Offline",
+			"overrides_strict is set, so the provider makes no AWS calls, and policy simulations can't be overridden.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(d.emulator.CheckService("iam")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := pooledClient(d.clients, "iam", d.cfg, func(cfg aws.Config) *iam.Client {
+		return iam.NewFromConfig(cfg)
+	})
 
 	// Build the simulation input
 	input, diags := d.buildSimulationInput(ctx, &data)
@@ -5,15 +5,23 @@ package provider
 
 import (
 	"context"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	fwtypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/internal/faults"
 )
 
 func TestIamPolicySimulationDataSource_Schema(t *testing.T) {
@@ -567,6 +575,219 @@ func TestBuildSimulationInput_WithResourcePolicy(t *testing.T) {
 	}
 }
 
+// readIamPolicySimulation runs the data source's Read against cfg with the
+// given configuration values; unset attributes are null.
+func readIamPolicySimulation(t *testing.T, cfg aws.Config, values map[string]tftypes.Value) (IamPolicySimulationDataSourceModel, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	d := &IamPolicySimulationDataSource{cfg: cfg}
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attrs := make(map[string]tftypes.Value, len(objType.AttributeTypes))
+	for name, typ := range objType.AttributeTypes {
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+		} else {
+			attrs[name] = tftypes.NewValue(typ, nil)
+		}
+	}
+
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, attrs)},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, nil)},
+	}
+	d.Read(ctx, req, &resp)
+
+	var model IamPolicySimulationDataSourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	}
+	return model, resp.Diagnostics
+}
+
+// tfStringList builds a list(string) configuration value.
+func tfStringList(values ...string) tftypes.Value {
+	elems := make([]tftypes.Value, len(values))
+	for i, v := range values {
+		elems[i] = tftypes.NewValue(tftypes.String, v)
+	}
+	return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elems)
+}
+
+func TestIamPolicySimulationDataSource_ReadCassette(t *testing.T) {
+	cfg := getCassetteConfig(t, "iam_policy_simulation")
+
+	model, diags := readIamPolicySimulation(t, cfg, map[string]tftypes.Value{
+		"policy_source_arn": tftypes.NewValue(tftypes.String, "arn:aws:iam::123456789012:role/app"),
+		"actions":           tfStringList("s3:GetObject", "s3:PutObject"),
+		"resource_arns":     tfStringList("arn:aws:s3:::assets/*"),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if model.Allowed.ValueBool() {
+		t.Error("expected allowed=false when one action is implicitly denied")
+	}
+	if !model.Error.IsNull() {
+		t.Errorf("expected no error, got %q", model.Error.ValueString())
+	}
+
+	var results []SimulationResultModel
+	if diags := model.Results.ElementsAs(context.Background(), &results, false); diags.HasError() {
+		t.Fatalf("failed to read results: %v", diags)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected results from both pages, got %d", len(results))
+	}
+	if results[0].Action.ValueString() != "s3:GetObject" || results[0].Decision.ValueString() != "allowed" {
+		t.Errorf("unexpected first result: %s %s", results[0].Action, results[0].Decision)
+	}
+	if results[1].Action.ValueString() != "s3:PutObject" || results[1].Decision.ValueString() != "implicitDeny" {
+		t.Errorf("unexpected second result: %s %s", results[1].Action, results[1].Decision)
+	}
+}
+
+func TestIamPolicySimulationDataSource_ReadFaults(t *testing.T) {
+	const principal = "arn:aws:iam::123456789012:role/app"
+
+	server, transport, cfg := getFaultConfig(t)
+	server.PutPrincipal(principal, fakeaws.Principal{
+		Decisions: map[string]string{"s3:GetObject": fakeaws.DecisionAllowed},
+	})
+	server.SetSimulationPageSize(1)
+
+	values := map[string]tftypes.Value{
+		"policy_source_arn": tftypes.NewValue(tftypes.String, principal),
+		"actions":           tfStringList("s3:GetObject", "s3:PutObject"),
+	}
+
+	t.Run("throttling is retried", func(t *testing.T) {
+		transport.Reset()
+		transport.InjectN("iam", fakeaws.OpSimulatePrincipalPolicy, 1, faults.Status(http.StatusBadRequest, "Throttling", "Rate exceeded"))
+
+		model, diags := readIamPolicySimulation(t, cfg, values)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if len(model.Results.Elements()) != 2 {
+			t.Errorf("expected 2 results, got %d", len(model.Results.Elements()))
+		}
+		if calls := transport.Calls(fakeaws.OpSimulatePrincipalPolicy); calls != 3 {
+			t.Errorf("expected 3 calls (one retry, two pages), got %d", calls)
+		}
+	})
+
+	t.Run("connection reset is retried", func(t *testing.T) {
+		transport.Reset()
+		transport.InjectN("iam", "", 1, faults.ConnectionReset())
+
+		if _, diags := readIamPolicySimulation(t, cfg, values); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+	})
+
+	t.Run("access denied is reported in the error attribute", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("iam", fakeaws.OpSimulatePrincipalPolicy, faults.Status(http.StatusForbidden, "AccessDenied", "not authorized"))
+
+		model, diags := readIamPolicySimulation(t, cfg, values)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if model.Allowed.ValueBool() {
+			t.Error("expected allowed=false")
+		}
+		if !strings.HasPrefix(model.Error.ValueString(), "access denied") {
+			t.Errorf("expected access denied error, got %q", model.Error.ValueString())
+		}
+		if calls := transport.Calls(fakeaws.OpSimulatePrincipalPolicy); calls != 1 {
+			t.Errorf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("expired credentials fail the read", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("iam", "", faults.ExpiredCredentials())
+
+		_, diags := readIamPolicySimulation(t, cfg, values)
+		if !diags.HasError() || diags[0].Summary() != "IAM Policy Simulation Failed" {
+			t.Fatalf("expected IAM Policy Simulation Failed, got %v", diags)
+		}
+		if !strings.Contains(diags[0].Detail(), "ExpiredToken") {
+			t.Errorf("expected ExpiredToken in detail, got %q", diags[0].Detail())
+		}
+	})
+
+	t.Run("malformed later page fails the read", func(t *testing.T) {
+		transport.Reset()
+		transport.InjectN("iam", fakeaws.OpSimulatePrincipalPolicy, 1, func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+			return next.RoundTrip(req)
+		})
+		transport.Inject("iam", fakeaws.OpSimulatePrincipalPolicy, faults.MalformedBody())
+
+		_, diags := readIamPolicySimulation(t, cfg, values)
+		if !diags.HasError() || diags[0].Summary() != "IAM Policy Simulation Failed" {
+			t.Fatalf("expected IAM Policy Simulation Failed, got %v", diags)
+		}
+	})
+}
+
+func TestAccIamPolicySimulation_fake(t *testing.T) {
+	server := testAccFakeAWS(t)
+	server.PutPrincipal("arn:aws:iam::123456789012:role/app", fakeaws.Principal{
+		Decisions: map[string]string{
+			"s3:GetObject": fakeaws.DecisionAllowed,
+			"s3:PutObject": fakeaws.DecisionAllowed,
+		},
+		PolicyID: "app-policy",
+	})
+	// Two actions against two resources spread over two pages.
+	server.SetSimulationPageSize(3)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccIamPolicySimulationConfig_fake,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.probe_iam_policy_simulation.allowed", "allowed", "true"),
+					resource.TestCheckResourceAttr("data.probe_iam_policy_simulation.allowed", "results.#", "4"),
+					resource.TestCheckResourceAttr("data.probe_iam_policy_simulation.allowed", "results.3.resource_arn", "arn:aws:s3:::b/*"),
+					resource.TestCheckResourceAttr("data.probe_iam_policy_simulation.allowed", "results.0.matched_statements.0.source_policy_id", "app-policy"),
+					resource.TestCheckResourceAttr("data.probe_iam_policy_simulation.denied", "allowed", "false"),
+					resource.TestCheckResourceAttr("data.probe_iam_policy_simulation.denied", "results.0.decision", "implicitDeny"),
+					resource.TestCheckResourceAttr("data.probe_iam_policy_simulation.missing", "allowed", "false"),
+					resource.TestCheckResourceAttr("data.probe_iam_policy_simulation.missing", "error", "principal not found: arn:aws:iam::123456789012:role/missing"),
+				),
+			},
+		},
+	})
+}
+
+const testAccIamPolicySimulationConfig_fake = `
+data "probe_iam_policy_simulation" "allowed" {
+  policy_source_arn = "arn:aws:iam::123456789012:role/app"
+  actions           = ["s3:GetObject", "s3:PutObject"]
+  resource_arns     = ["arn:aws:s3:::a/*", "arn:aws:s3:::b/*"]
+}
+
+data "probe_iam_policy_simulation" "denied" {
+  policy_source_arn = "arn:aws:iam::123456789012:role/app"
+  actions           = ["iam:CreateUser"]
+}
+
+data "probe_iam_policy_simulation" "missing" {
+  policy_source_arn = "arn:aws:iam::123456789012:role/missing"
+  actions           = ["s3:GetObject"]
+}
+`
+
 // testAccIamPolicySimulationPreCheck validates that acceptance tests can run.
 // LocalStack does not support SimulatePrincipalPolicy, so these tests require
 // real AWS credentials with iam:SimulatePrincipalPolicy permission.
@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// EnvLocalStackHost is LocalStack's own variable for the host and port
+	// it listens on, e.g. "localstack:4566" in a Docker Compose network.
+	EnvLocalStackHost = "LOCALSTACK_HOST"
+
+	// EnvAWSEndpointURL is the AWS SDK's global endpoint override.
+	EnvAWSEndpointURL = "AWS_ENDPOINT_URL"
+
+	defaultLocalStackEndpoint = "http://localhost:4566"
+	defaultLocalStackPort     = "4566"
+	localStackHealthPath      = "/_localstack/health"
+)
+
+// localStackEndpoint returns where LocalStack is expected: AWS_ENDPOINT_URL
+// if set, then LOCALSTACK_HOST, then localhost:4566.
+func localStackEndpoint() string {
+	if endpoint := os.Getenv(EnvAWSEndpointURL); endpoint != "" {
+		return strings.TrimSuffix(endpoint, "/")
+	}
+	if host := os.Getenv(EnvLocalStackHost); host != "" {
+		return localStackHostEndpoint(host)
+	}
+	return defaultLocalStackEndpoint
+}
+
+// localStackHostEndpoint converts a LOCALSTACK_HOST value (host[:port],
+// optionally with a scheme) to a URL.
+func localStackHostEndpoint(host string) string {
+	host = strings.TrimSuffix(host, "/")
+	if strings.Contains(host, "://") {
+		return host
+	}
+	if !strings.Contains(host, ":") {
+		host += ":" + defaultLocalStackPort
+	}
+	return "http://" + host
+}
+
+// detectLocalStack returns the LocalStack instance at localStackEndpoint, or
+// nil if nothing there answers like LocalStack's health endpoint.
+func detectLocalStack(ctx context.Context) *EmulatorInfo {
+	info, err := fetchLocalStackHealth(ctx, localStackEndpoint())
+	if err != nil {
+		return nil
+	}
+	return info
+}
+
+// fetchLocalStackHealth reads the health endpoint of the LocalStack instance
+// at endpoint.
+func fetchLocalStackHealth(ctx context.Context, endpoint string) (*EmulatorInfo, error) {
+	resp, err := getHealth(ctx, endpoint+localStackHealthPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %s", localStackHealthPath, resp.Status)
+	}
+
+	var health struct {
+		Services map[string]string `json:"services"`
+		Edition  string            `json:"edition"`
+		Version  string            `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, fmt.Errorf("%s: %w", localStackHealthPath, err)
+	}
+	if health.Services == nil {
+		return nil, fmt.Errorf("%s: response has no services", localStackHealthPath)
+	}
+
+	return &EmulatorInfo{
+		Name:     EmulatorLocalStack,
+		Endpoint: endpoint,
+		Edition:  health.Edition,
+		Version:  health.Version,
+		Services: health.Services,
+	}, nil
+}
@@ -0,0 +1,542 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+	"github.com/shakefu/terraform-provider-probe/probe/awsprobe"
+)
+
+// newLocalStackServer serves health as LocalStack's health endpoint.
+func newLocalStackServer(t *testing.T, health string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != localStackHealthPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(health))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+const testLocalStackHealth = `{
+  "services": {"dynamodb": "running", "s3": "available", "iam": "disabled", "kinesis": "error"},
+  "edition": "community",
+  "version": "3.8.1"
+}`
+
+func TestLocalStackEndpoint(t *testing.T) {
+	tests := []struct {
+		name          string
+		endpointURL   string
+		localStackEnv string
+		expected      string
+	}{
+		{name: "default", expected: "http://localhost:4566"},
+		{name: "AWS_ENDPOINT_URL", endpointURL: "http://localstack:4566/", expected: "http://localstack:4566"},
+		{name: "AWS_ENDPOINT_URL wins", endpointURL: "http://a:1", localStackEnv: "b:2", expected: "http://a:1"},
+		{name: "LOCALSTACK_HOST with port", localStackEnv: "localstack:4510", expected: "http://localstack:4510"},
+		{name: "LOCALSTACK_HOST without port", localStackEnv: "localhost.localstack.cloud", expected: "http://localhost.localstack.cloud:4566"},
+		{name: "LOCALSTACK_HOST with scheme", localStackEnv: "https://ls.example.com", expected: "https://ls.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvAWSEndpointURL, tt.endpointURL)
+			t.Setenv(EnvLocalStackHost, tt.localStackEnv)
+
+			if got := localStackEndpoint(); got != tt.expected {
+				t.Errorf("localStackEndpoint() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetectLocalStack(t *testing.T) {
+	t.Run("AWS_ENDPOINT_URL", func(t *testing.T) {
+		server := newLocalStackServer(t, testLocalStackHealth)
+		t.Setenv(EnvAWSEndpointURL, server.URL)
+
+		info := detectLocalStack(context.Background())
+		if info == nil {
+			t.Fatal("expected LocalStack to be detected")
+		}
+		if info.Endpoint != server.URL {
+			t.Errorf("Endpoint = %q, want %q", info.Endpoint, server.URL)
+		}
+		if info.Edition != "community" || info.Version != "3.8.1" {
+			t.Errorf("unexpected edition/version %q/%q", info.Edition, info.Version)
+		}
+		if info.Services["dynamodb"] != "running" {
+			t.Errorf("expected dynamodb running, got %v", info.Services)
+		}
+	})
+
+	t.Run("LOCALSTACK_HOST", func(t *testing.T) {
+		server := newLocalStackServer(t, testLocalStackHealth)
+		t.Setenv(EnvAWSEndpointURL, "")
+		t.Setenv(EnvLocalStackHost, strings.TrimPrefix(server.URL, "http://"))
+
+		if info := detectLocalStack(context.Background()); info == nil || info.Endpoint != server.URL {
+			t.Fatalf("expected LocalStack at %s, got %+v", server.URL, info)
+		}
+	})
+
+	t.Run("200 without health document", func(t *testing.T) {
+		server := newLocalStackServer(t, `<html>ok</html>`)
+		t.Setenv(EnvAWSEndpointURL, server.URL)
+
+		if info := detectLocalStack(context.Background()); info != nil {
+			t.Errorf("expected no detection, got %+v", info)
+		}
+	})
+
+	t.Run("nothing listening", func(t *testing.T) {
+		server := newLocalStackServer(t, testLocalStackHealth)
+		server.Close()
+		t.Setenv(EnvAWSEndpointURL, server.URL)
+
+		if info := detectLocalStack(context.Background()); info != nil {
+			t.Errorf("expected no detection, got %+v", info)
+		}
+	})
+}
+
+func TestLoadAWSConfig_LocalStack(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	server := newLocalStackServer(t, testLocalStackHealth)
+
+	t.Run("auto-detected from LOCALSTACK_HOST", func(t *testing.T) {
+		t.Setenv(EnvAWSEndpointURL, "")
+		t.Setenv(EnvLocalStackHost, strings.TrimPrefix(server.URL, "http://"))
+
+		cfg, info, err := loadAWSConfig(context.Background(), AWSSettings{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info == nil || cfg.BaseEndpoint == nil || *cfg.BaseEndpoint != server.URL {
+			t.Fatalf("expected endpoint %s, got %v (info %+v)", server.URL, cfg.BaseEndpoint, info)
+		}
+		if _, err := cfg.Credentials.Retrieve(context.Background()); err != nil {
+			t.Errorf("expected dummy credentials, got %v", err)
+		}
+	})
+
+	t.Run("explicit endpoint reads health", func(t *testing.T) {
+		_, info, err := loadAWSConfig(context.Background(), AWSSettings{Endpoint: server.URL})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info == nil || info.Services["iam"] != "disabled" {
+			t.Fatalf("expected services from health endpoint, got %+v", info)
+		}
+	})
+
+	t.Run("explicit endpoint without health", func(t *testing.T) {
+		_, info, err := loadAWSConfig(context.Background(), AWSSettings{Endpoint: "http://127.0.0.1:1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info == nil || info.Endpoint != "http://127.0.0.1:1" || info.Services != nil {
+			t.Fatalf("expected endpoint without services, got %+v", info)
+		}
+	})
+}
+
+func TestEmulatorInfo_CheckService_LocalStack(t *testing.T) {
+	info := &EmulatorInfo{
+		Name:     EmulatorLocalStack,
+		Endpoint: "http://localhost:4566",
+		Services: map[string]string{"dynamodb": "running", "s3": "available", "iam": "disabled", "kinesis": "error"},
+	}
+
+	for service, wantErr := range map[string]bool{
+		"dynamodb": false,
+		"s3":       false,
+		"iam":      true,
+		"kinesis":  true,
+		"DynamoDB": false,
+		"unlisted": false,
+	} {
+		if got := info.CheckService(service).HasError(); got != wantErr {
+			t.Errorf("CheckService(%q) error = %v, want %v", service, got, wantErr)
+		}
+	}
+
+	var none *EmulatorInfo
+	if none.CheckService("iam").HasError() {
+		t.Error("nil EmulatorInfo should not report errors")
+	}
+	if (&EmulatorInfo{Name: EmulatorLocalStack}).CheckService("iam").HasError() {
+		t.Error("unknown services should not report errors")
+	}
+}
+
+func TestProbeDataSource_LocalStackServiceDisabled(t *testing.T) {
+	_, cfg := getFakeAWSConfig(t)
+	d := &ProbeDataSource{
+		cfg:      cfg,
+		registry: probe.NewProberRegistry(cfg),
+		emulator: &EmulatorInfo{
+			Name:     EmulatorLocalStack,
+			Endpoint: "http://localhost:4566",
+			Services: map[string]string{"dynamodb": "disabled"},
+		},
+	}
+
+	_, diags := readProbe(t, d, map[string]tftypes.Value{
+		"type": tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+		"id":   tftypes.NewValue(tftypes.String, "orders"),
+	})
+	if !diags.HasError() || diags[0].Summary() != "Service not available in LocalStack" {
+		t.Fatalf("expected LocalStack service diagnostic, got %v", diags)
+	}
+	if !strings.Contains(diags[0].Detail(), `"dynamodb" service as disabled`) {
+		t.Errorf("unexpected detail %q", diags[0].Detail())
+	}
+}
+
+func TestDynamoDBProber_TableNotFound(t *testing.T) {
+	cfg := getLocalStackConfig(t)
+	if cfg == nil {
+		t.Skip("LocalStack not available")
+	}
+
+	prober := awsprobe.NewDynamoDBProber(*cfg)
+	result, err := prober.Probe(context.Background(), "nonexistent-table-12345")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Exists {
+		t.Error("expected Exists to be false for nonexistent table")
+	}
+}
+
+func TestDynamoDBProber_TableExists(t *testing.T) {
+	cfg := getLocalStackConfig(t)
+	if cfg == nil {
+		t.Skip("LocalStack not available")
+	}
+
+	ctx := context.Background()
+	client := dynamodb.NewFromConfig(*cfg)
+	tableName := "probe-test-dynamodb-exists"
+
+	// Create a test table
+	_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("pk"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("pk"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		t.Fatalf("failed to create test table: %v", err)
+	}
+
+	// Clean up after test
+	t.Cleanup(func() {
+		_, _ = client.DeleteTable(ctx, &dynamodb.DeleteTableInput{
+			TableName: aws.String(tableName),
+		})
+	})
+
+	// Wait for table to be active
+	waiter := dynamodb.NewTableExistsWaiter(client)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	}, 30_000_000_000); err != nil { // 30 seconds
+		t.Fatalf("table did not become active: %v", err)
+	}
+
+	// Test the prober
+	prober := awsprobe.NewDynamoDBProber(*cfg)
+	result, err := prober.Probe(ctx, tableName)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Exists {
+		t.Error("expected Exists to be true for existing table")
+	}
+
+	if result.Arn == "" {
+		t.Error("expected ARN to be populated")
+	}
+
+	if result.TerraformType != "aws_dynamodb_table" || result.ImportID != tableName {
+		t.Errorf("expected import target aws_dynamodb_table/%s, got %s/%s", tableName, result.TerraformType, result.ImportID)
+	}
+
+	if result.Properties["TableName"] != tableName {
+		t.Errorf("expected TableName=%q, got %q", tableName, result.Properties["TableName"])
+	}
+}
+
+func TestDynamoDBProber_TableWithTags(t *testing.T) {
+	cfg := getLocalStackConfig(t)
+	if cfg == nil {
+		t.Skip("LocalStack not available")
+	}
+
+	ctx := context.Background()
+	client := dynamodb.NewFromConfig(*cfg)
+	tableName := "probe-test-dynamodb-tags"
+
+	// Create a test table with tags
+	_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("pk"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("pk"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+		Tags: []types.Tag{
+			{Key: aws.String("Environment"), Value: aws.String("test")},
+			{Key: aws.String("Owner"), Value: aws.String("probe-provider")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create test table: %v", err)
+	}
+
+	// Clean up after test
+	t.Cleanup(func() {
+		_, _ = client.DeleteTable(ctx, &dynamodb.DeleteTableInput{
+			TableName: aws.String(tableName),
+		})
+	})
+
+	// Wait for table to be active
+	waiter := dynamodb.NewTableExistsWaiter(client)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	}, 30_000_000_000); err != nil { // 30 seconds
+		t.Fatalf("table did not become active: %v", err)
+	}
+
+	// Test the prober
+	prober := awsprobe.NewDynamoDBProber(*cfg)
+	result, err := prober.Probe(ctx, tableName)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Exists {
+		t.Error("expected Exists to be true for existing table")
+	}
+
+	// Check tags
+	if result.Tags == nil {
+		t.Fatal("expected Tags to be populated")
+	}
+
+	if result.Tags["Environment"] != "test" {
+		t.Errorf("expected Environment tag='test', got %q", result.Tags["Environment"])
+	}
+
+	if result.Tags["Owner"] != "probe-provider" {
+		t.Errorf("expected Owner tag='probe-provider', got %q", result.Tags["Owner"])
+	}
+
+	// Check tags in properties
+	propTags, ok := result.Properties["Tags"].(map[string]string)
+	if !ok {
+		t.Error("expected Tags in Properties to be map[string]string")
+	} else if propTags["Environment"] != "test" {
+		t.Errorf("expected Properties.Tags.Environment='test', got %q", propTags["Environment"])
+	}
+}
+
+func TestS3Prober_BucketNotFound(t *testing.T) {
+	cfg := getLocalStackConfig(t)
+	if cfg == nil {
+		t.Skip("LocalStack not available")
+	}
+
+	prober := awsprobe.NewS3Prober(*cfg)
+	result, err := prober.Probe(context.Background(), "nonexistent-bucket-12345-xyz")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Exists {
+		t.Error("expected Exists to be false for nonexistent bucket")
+	}
+}
+
+func TestS3Prober_BucketExists(t *testing.T) {
+	cfg := getLocalStackConfig(t)
+	if cfg == nil {
+		t.Skip("LocalStack not available")
+	}
+
+	ctx := context.Background()
+	client := s3.NewFromConfig(*cfg, func(o *s3.Options) {
+		o.UsePathStyle = true
+	})
+	bucketName := "probe-test-s3-exists"
+
+	// Create a test bucket
+	_, err := client.CreateBucket(ctx, &s3.CreateBucketInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		t.Fatalf("failed to create test bucket: %v", err)
+	}
+
+	// Clean up after test
+	t.Cleanup(func() {
+		_, _ = client.DeleteBucket(ctx, &s3.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+	})
+
+	// Test the prober
+	prober := awsprobe.NewS3Prober(*cfg)
+	result, err := prober.Probe(ctx, bucketName)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Exists {
+		t.Error("expected Exists to be true for existing bucket")
+	}
+
+	if result.Arn == "" {
+		t.Error("expected ARN to be populated")
+	}
+
+	expectedArn := "arn:aws:s3:::" + bucketName
+	if result.Arn != expectedArn {
+		t.Errorf("expected ARN=%q, got %q", expectedArn, result.Arn)
+	}
+
+	if result.TerraformType != "aws_s3_bucket" || result.ImportID != bucketName {
+		t.Errorf("expected import target aws_s3_bucket/%s, got %s/%s", bucketName, result.TerraformType, result.ImportID)
+	}
+
+	if result.Properties["BucketName"] != bucketName {
+		t.Errorf("expected BucketName=%q, got %q", bucketName, result.Properties["BucketName"])
+	}
+}
+
+func TestS3Prober_BucketWithTags(t *testing.T) {
+	cfg := getLocalStackConfig(t)
+	if cfg == nil {
+		t.Skip("LocalStack not available")
+	}
+
+	ctx := context.Background()
+	client := s3.NewFromConfig(*cfg, func(o *s3.Options) {
+		o.UsePathStyle = true
+	})
+	bucketName := "probe-test-s3-tags"
+
+	// Create a test bucket
+	_, err := client.CreateBucket(ctx, &s3.CreateBucketInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		t.Fatalf("failed to create test bucket: %v", err)
+	}
+
+	// Add tags
+	_, err = client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket: aws.String(bucketName),
+		Tagging: &s3types.Tagging{
+			TagSet: []s3types.Tag{
+				{Key: aws.String("Environment"), Value: aws.String("test")},
+				{Key: aws.String("Owner"), Value: aws.String("probe-provider")},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to tag bucket: %v", err)
+	}
+
+	// Clean up after test
+	t.Cleanup(func() {
+		_, _ = client.DeleteBucket(ctx, &s3.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+	})
+
+	// Test the prober
+	prober := awsprobe.NewS3Prober(*cfg)
+	result, err := prober.Probe(ctx, bucketName)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Exists {
+		t.Error("expected Exists to be true for existing bucket")
+	}
+
+	// Check tags
+	if result.Tags == nil {
+		t.Fatal("expected Tags to be populated")
+	}
+
+	if result.Tags["Environment"] != "test" {
+		t.Errorf("expected Environment tag='test', got %q", result.Tags["Environment"])
+	}
+
+	if result.Tags["Owner"] != "probe-provider" {
+		t.Errorf("expected Owner tag='probe-provider', got %q", result.Tags["Owner"])
+	}
+
+	// Check tags in properties
+	propTags, ok := result.Properties["Tags"].(map[string]string)
+	if !ok {
+		t.Error("expected Tags in Properties to be map[string]string")
+	} else if propTags["Environment"] != "test" {
+		t.Errorf("expected Properties.Tags.Environment='test', got %q", propTags["Environment"])
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Policies for probes that fail unexpectedly, e.g. because AWS is throttling
+// or unreachable. A resource that doesn't exist is not a failure.
+const (
+	// OnErrorFail fails the read. It is the default.
+	OnErrorFail = "fail"
+
+	// OnErrorUnknown reports a warning and leaves exists null, so nothing
+	// is decided from the failed probe.
+	OnErrorUnknown = "unknown"
+
+	// OnErrorAssumeMissing reports a warning and treats the resource as
+	// missing.
+	OnErrorAssumeMissing = "assume_missing"
+
+	// OnErrorAssumePresent reports a warning and treats the resource as
+	// existing, with nothing else known about it.
+	OnErrorAssumePresent = "assume_present"
+)
+
+// OnErrorPolicies returns every on_error policy.
+func OnErrorPolicies() []string {
+	return []string{OnErrorFail, OnErrorUnknown, OnErrorAssumeMissing, OnErrorAssumePresent}
+}
+
+// validateOnError checks an on_error argument at attr.
+func validateOnError(attr path.Path, policy types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if policy.IsNull() || policy.IsUnknown() {
+		return diags
+	}
+
+	switch policy.ValueString() {
+	case OnErrorFail, OnErrorUnknown, OnErrorAssumeMissing, OnErrorAssumePresent:
+	default:
+		diags.AddAttributeError(
+			attr,
+			"Invalid on_error policy",
+			fmt.Sprintf("%q is not an on_error policy. Use one of: %s.", policy.ValueString(), strings.Join(OnErrorPolicies(), ", ")),
+		)
+	}
+	return diags
+}
+
+// onErrorPolicy returns the policy a probe follows: its own on_error if set,
+// otherwise the provider's.
+func onErrorPolicy(policy types.String, providerPolicy string) string {
+	if !policy.IsNull() {
+		return policy.ValueString()
+	}
+	if providerPolicy != "" {
+		return providerPolicy
+	}
+	return OnErrorFail
+}
+
+// tolerateProbeError decides whether a failed probe continues under policy.
+// If it does, it sets the fallback values and error on data and returns a
+// warning; otherwise it returns the error. Strict overrides always fail,
+// since they exist to catch probes the overrides miss.
+func tolerateProbeError(data *ProbeDataSourceModel, policy string, err error) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if policy == OnErrorFail || errors.Is(err, errNoOverride) {
+		diags.AddError("Probe failed", err.Error())
+		return diags
+	}
+
+	var outcome string
+	switch policy {
+	case OnErrorAssumeMissing:
+		data.Exists = types.BoolValue(false)
+		outcome = "the resource is assumed to be missing"
+	case OnErrorAssumePresent:
+		data.Exists = types.BoolValue(true)
+		outcome = "the resource is assumed to exist"
+	default:
+		data.Exists = types.BoolNull()
+		outcome = "exists is left null"
+	}
+
+	data.Error = types.StringValue(err.Error())
+	data.Arn = types.StringNull()
+	data.Properties = types.DynamicNull()
+	data.PropertiesJSON = types.StringNull()
+	data.Sensitive = types.DynamicNull()
+	data.Status = types.StringNull()
+	data.LifecycleState = types.StringNull()
+	data.TerraformType = types.StringNull()
+	data.ImportID = types.StringNull()
+	data.Compatible, data.Conflicts = types.BoolNull(), types.ListNull(types.StringType)
+	data.Owned, data.Foreign, data.Untagged = types.BoolNull(), types.BoolNull(), types.BoolNull()
+
+	diags.AddWarning(
+		"Probe failed",
+		fmt.Sprintf("%s\n\non_error is %q, so %s. The error attribute holds the message.", err, policy, outcome),
+	)
+	return diags
+}
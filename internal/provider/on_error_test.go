@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+func TestOnErrorPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   types.String
+		provider string
+		expected string
+	}{
+		{name: "default", policy: types.StringNull(), expected: OnErrorFail},
+		{name: "provider", policy: types.StringNull(), provider: OnErrorUnknown, expected: OnErrorUnknown},
+		{name: "data source wins", policy: types.StringValue(OnErrorAssumePresent), provider: OnErrorUnknown, expected: OnErrorAssumePresent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := onErrorPolicy(tt.policy, tt.provider); got != tt.expected {
+				t.Errorf("onErrorPolicy() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProbeDataSource_OnError(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutTable(fakeaws.Table{Name: "orders"})
+	server.Fail(fakeaws.OpDescribeTable, fakeaws.Error{Status: http.StatusBadRequest, Code: "AccessDeniedException", Message: "denied"})
+
+	d := &ProbeDataSource{registry: probe.NewProberRegistry(cfg)}
+	read := func(d *ProbeDataSource, policy string) (ProbeDataSourceModel, diag.Diagnostics) {
+		onError := tftypes.NewValue(tftypes.String, nil)
+		if policy != "" {
+			onError = tftypes.NewValue(tftypes.String, policy)
+		}
+		return readProbe(t, d, map[string]tftypes.Value{
+			"type":     tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+			"id":       tftypes.NewValue(tftypes.String, "orders"),
+			"on_error": onError,
+		})
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		for _, policy := range []string{"", OnErrorFail} {
+			if _, diags := read(d, policy); !diags.HasError() {
+				t.Errorf("on_error %q: expected the read to fail", policy)
+			}
+		}
+	})
+
+	tests := []struct {
+		policy string
+		exists types.Bool
+	}{
+		{policy: OnErrorUnknown, exists: types.BoolNull()},
+		{policy: OnErrorAssumeMissing, exists: types.BoolValue(false)},
+		{policy: OnErrorAssumePresent, exists: types.BoolValue(true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			data, diags := read(d, tt.policy)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if diags.WarningsCount() != 1 {
+				t.Errorf("expected a warning, got %v", diags)
+			}
+			if !data.Exists.Equal(tt.exists) {
+				t.Errorf("exists = %v, want %v", data.Exists, tt.exists)
+			}
+			if !strings.Contains(data.Error.ValueString(), "AccessDeniedException") {
+				t.Errorf("error = %q", data.Error.ValueString())
+			}
+			if !data.Arn.IsNull() || !data.Properties.IsNull() || !data.LifecycleState.IsNull() {
+				t.Error("expected the other attributes to be null")
+			}
+		})
+	}
+
+	t.Run("provider policy", func(t *testing.T) {
+		data, diags := read(&ProbeDataSource{registry: d.registry, onError: OnErrorAssumeMissing}, "")
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if data.Exists.ValueBool() {
+			t.Error("expected the provider's on_error to apply")
+		}
+	})
+
+	t.Run("success clears error", func(t *testing.T) {
+		server.ClearFaults()
+		data, diags := read(d, OnErrorAssumeMissing)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !data.Exists.ValueBool() || !data.Error.IsNull() {
+			t.Errorf("exists = %v, error = %v", data.Exists, data.Error)
+		}
+	})
+
+	t.Run("strict overrides still fail", func(t *testing.T) {
+		registry := probe.NewProberRegistryWithCatalog(cfg, ApplyOverrides(probe.DefaultCatalog(), nil, true))
+		_, diags := read(&ProbeDataSource{registry: registry}, OnErrorAssumeMissing)
+		if !diags.HasError() {
+			t.Fatal("expected the read to fail")
+		}
+	})
+}
+
+func TestProbeProvider_ConfigureOnError(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	values := map[string]tftypes.Value{
+		"localstack": tftypes.NewValue(tftypes.Bool, false),
+		"on_error":   tftypes.NewValue(tftypes.String, OnErrorUnknown),
+	}
+	providerData, diags := configureProvider(t, &ProbeProvider{catalog: probe.DefaultCatalog()}, values)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if providerData.OnError != OnErrorUnknown {
+		t.Errorf("OnError = %q, want %q", providerData.OnError, OnErrorUnknown)
+	}
+
+	values["on_error"] = tftypes.NewValue(tftypes.String, "retry")
+	if _, diags := configureProvider(t, &ProbeProvider{catalog: probe.DefaultCatalog()}, values); !diags.HasError() {
+		t.Error("expected an invalid on_error to fail")
+	}
+}
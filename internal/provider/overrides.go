@@ -0,0 +1,316 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// Override is a canned probe result, used instead of calling AWS.
+type Override struct {
+	// Exists defaults to true; set it to false to simulate a missing
+	// resource.
+	Exists *bool `json:"exists,omitempty"`
+
+	Arn    string `json:"arn,omitempty"`
+	Status string `json:"status,omitempty"`
+
+	// LifecycleState defaults to the state Status maps to.
+	LifecycleState string `json:"lifecycle_state,omitempty"`
+
+	Properties map[string]any    `json:"properties,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+
+	// TerraformType and ImportID default to the canonical type and the
+	// identifier.
+	TerraformType string `json:"terraform_type,omitempty"`
+	ImportID      string `json:"import_id,omitempty"`
+}
+
+// result converts the override to the ProbeResult for identifier.
+func (o Override) result(canonicalType, identifier string) *probe.ProbeResult {
+	if o.Exists != nil && !*o.Exists {
+		return &probe.ProbeResult{Exists: false}
+	}
+
+	result := &probe.ProbeResult{
+		Exists:         true,
+		Arn:            o.Arn,
+		Status:         o.Status,
+		LifecycleState: o.LifecycleState,
+		Properties:     o.Properties,
+		Tags:           o.Tags,
+		TerraformType:  o.TerraformType,
+		ImportID:       o.ImportID,
+	}
+	if result.TerraformType == "" {
+		result.TerraformType = canonicalType
+	}
+	if result.ImportID == "" {
+		result.ImportID = identifier
+	}
+	return result
+}
+
+// Overrides maps canonical resource types to overrides by identifier.
+type Overrides map[string]map[string]Override
+
+// ParseOverrides decodes a JSON object keyed by "type/id". Types may be any
+// name catalog recognizes and are stored under their canonical name.
+func ParseOverrides(data []byte, catalog *probe.Catalog) (Overrides, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var raw map[string]Override
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	overrides := make(Overrides)
+	var errs []error
+	for _, key := range keys {
+		typeName, id, ok := strings.Cut(key, "/")
+		if !ok || typeName == "" || id == "" {
+			errs = append(errs, fmt.Errorf("override %q: key must have the form type/id", key))
+			continue
+		}
+
+		canonical := catalog.Normalize(typeName)
+		if _, ok := catalog.Factory(canonical); !ok {
+			errs = append(errs, fmt.Errorf("override %q: unsupported resource type %q", key, typeName))
+			continue
+		}
+
+		if _, ok := overrides[canonical][id]; ok {
+			errs = append(errs, fmt.Errorf("override %q: %s/%s is already overridden", key, canonical, id))
+			continue
+		}
+		if overrides[canonical] == nil {
+			overrides[canonical] = make(map[string]Override)
+		}
+		if state := raw[key].LifecycleState; state != "" && !slices.Contains(probe.LifecycleStates(), state) {
+			errs = append(errs, fmt.Errorf("override %q: lifecycle_state must be one of %s, got %q",
+				key, strings.Join(probe.LifecycleStates(), ", "), state))
+			continue
+		}
+		overrides[canonical][id] = raw[key]
+	}
+
+	return overrides, errors.Join(errs...)
+}
+
+// LoadOverrides reads overrides from a JSON file.
+func LoadOverrides(path string, catalog *probe.Catalog) (Overrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := ParseOverrides(data, catalog)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return overrides, nil
+}
+
+// inlineOverrides converts the overrides provider setting by way of JSON,
+// so it is validated exactly like an overrides file.
+func inlineOverrides(ctx context.Context, value types.Dynamic, catalog *probe.Catalog) (Overrides, error) {
+	tfValue, err := value.UnderlyingValue().ToTerraformValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tftypesToGo(tfValue)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	return ParseOverrides(data, catalog)
+}
+
+// ApplyOverrides returns a copy of catalog whose probers answer from
+// overrides. Other identifiers are probed as usual, unless strict is set,
+// in which case probing them is an error.
+func ApplyOverrides(catalog *probe.Catalog, overrides Overrides, strict bool) *probe.Catalog {
+	clone := catalog.Clone()
+	for _, canonical := range catalog.Types() {
+		byID, ok := overrides[canonical]
+		if !ok && !strict {
+			continue
+		}
+
+		factory, _ := catalog.Factory(canonical)
+		clone.Register(canonical, catalog.Aliases(canonical), func(cfg aws.Config) probe.ResourceProber {
+			return &overrideProber{
+				canonicalType: canonical,
+				overrides:     byID,
+				strict:        strict,
+				inner:         func() probe.ResourceProber { return factory(cfg) },
+			}
+		})
+	}
+	return clone
+}
+
+// errNoOverride is returned in strict mode for identifiers without an
+// override. It always fails the probe, whatever on_error says.
+var errNoOverride = errors.New("no override")
+
+// overrideProber answers probes from overrides before falling back to the
+// prober it wraps.
+type overrideProber struct {
+	canonicalType string
+	overrides     map[string]Override
+	strict        bool
+
+	// inner builds the wrapped prober. It is only called when needed, so
+	// strict mode never probes with it.
+	inner  func() probe.ResourceProber
+	once   sync.Once
+	prober probe.ResourceProber
+}
+
+func (p *overrideProber) Probe(ctx context.Context, identifier string) (*probe.ProbeResult, error) {
+	if override, ok := p.overrides[identifier]; ok {
+		return override.result(p.canonicalType, identifier), nil
+	}
+	if p.strict {
+		return nil, fmt.Errorf("%w for %s/%s (overrides_strict is set)", errNoOverride, p.canonicalType, identifier)
+	}
+	return p.wrapped().Probe(ctx, identifier)
+}
+
+// Service implements probe.ServiceProber. It is empty in strict mode, where
+// no requests are sent.
+func (p *overrideProber) Service() string {
+	if p.strict {
+		return ""
+	}
+	if sp, ok := p.wrapped().(probe.ServiceProber); ok {
+		return sp.Service()
+	}
+	return ""
+}
+
+// ImmutableProperties implements probe.ImmutableProber.
+func (p *overrideProber) ImmutableProperties() []probe.ImmutableProperty {
+	if ip, ok := p.wrapped().(probe.ImmutableProber); ok {
+		return ip.ImmutableProperties()
+	}
+	return nil
+}
+
+// SensitiveProperties implements probe.SensitiveProber, so overridden
+// properties are classified like the wrapped prober's.
+func (p *overrideProber) SensitiveProperties() []string {
+	if sp, ok := p.wrapped().(probe.SensitiveProber); ok {
+		return sp.SensitiveProperties()
+	}
+	return nil
+}
+
+// ValidateIdentifier implements probe.IdentifierValidator.
+func (p *overrideProber) ValidateIdentifier(identifier string) error {
+	if iv, ok := p.wrapped().(probe.IdentifierValidator); ok {
+		return iv.ValidateIdentifier(identifier)
+	}
+	return nil
+}
+
+// LintIdentifier implements probe.IdentifierLinter.
+func (p *overrideProber) LintIdentifier(identifier string) error {
+	if il, ok := p.wrapped().(probe.IdentifierLinter); ok {
+		return il.LintIdentifier(identifier)
+	}
+	return nil
+}
+
+func (p *overrideProber) wrapped() probe.ResourceProber {
+	p.once.Do(func() { p.prober = p.inner() })
+	return p.prober
+}
+
+// tftypesToGo converts a Terraform value to the Go values encoding/json
+// produces, so inline overrides decode like an overrides file.
+func tftypesToGo(v tftypes.Value) (any, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	if !v.IsKnown() {
+		return nil, errors.New("value is not known")
+	}
+
+	typ := v.Type()
+	switch {
+	case typ.Is(tftypes.String):
+		var s string
+		err := v.As(&s)
+		return s, err
+	case typ.Is(tftypes.Number):
+		var n big.Float
+		if err := v.As(&n); err != nil {
+			return nil, err
+		}
+		f, _ := n.Float64()
+		return f, nil
+	case typ.Is(tftypes.Bool):
+		var b bool
+		err := v.As(&b)
+		return b, err
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}), typ.Is(tftypes.Tuple{}):
+		var elems []tftypes.Value
+		if err := v.As(&elems); err != nil {
+			return nil, err
+		}
+		list := make([]any, len(elems))
+		for i, elem := range elems {
+			converted, err := tftypesToGo(elem)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = converted
+		}
+		return list, nil
+	case typ.Is(tftypes.Map{}), typ.Is(tftypes.Object{}):
+		var attrs map[string]tftypes.Value
+		if err := v.As(&attrs); err != nil {
+			return nil, err
+		}
+		obj := make(map[string]any, len(attrs))
+		for k, attr := range attrs {
+			converted, err := tftypesToGo(attr)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			obj[k] = converted
+		}
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+}
@@ -0,0 +1,371 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+const testOverrides = `{
+  "AWS::DynamoDB::Table/orders": {
+    "arn": "arn:aws:dynamodb:us-east-1:123456789012:table/orders",
+    "status": "ACTIVE",
+    "properties": {"BillingModeSummary": {"BillingMode": "PAY_PER_REQUEST"}},
+    "tags": {"Team": "payments"}
+  },
+  "aws_s3_bucket/gone": {"exists": false}
+}`
+
+func TestParseOverrides(t *testing.T) {
+	catalog := probe.DefaultCatalog()
+
+	overrides, err := ParseOverrides([]byte(testOverrides), catalog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	orders, ok := overrides["aws_dynamodb_table"]["orders"]
+	if !ok {
+		t.Fatalf("expected alias to be stored under the canonical type, got %v", overrides)
+	}
+	result := orders.result("aws_dynamodb_table", "orders")
+	if !result.Exists || result.Status != "ACTIVE" || result.Tags["Team"] != "payments" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.TerraformType != "aws_dynamodb_table" || result.ImportID != "orders" {
+		t.Errorf("expected default terraform_type and import_id, got %q, %q", result.TerraformType, result.ImportID)
+	}
+
+	if result.Lifecycle() != probe.LifecycleActive {
+		t.Errorf("expected the lifecycle state to follow the status, got %q", result.Lifecycle())
+	}
+
+	if gone := overrides["aws_s3_bucket"]["gone"].result("aws_s3_bucket", "gone"); gone.Exists {
+		t.Error("expected exists = false to be honored")
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "unknown type", data: `{"aws_sqs_quue/jobs": {}}`, wantErr: `unsupported resource type "aws_sqs_quue"`},
+		{name: "missing id", data: `{"aws_s3_bucket": {}}`, wantErr: "type/id"},
+		{name: "unknown field", data: `{"aws_s3_bucket/a": {"exist": true}}`, wantErr: `unknown field "exist"`},
+		{name: "unknown lifecycle state", data: `{"aws_s3_bucket/a": {"lifecycle_state": "deleted"}}`, wantErr: `lifecycle_state must be one of`},
+		{name: "duplicate via alias", data: `{"aws_dynamodb_table/a": {}, "AWS::DynamoDB::Table/a": {}}`, wantErr: "already overridden"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOverrides([]byte(tt.data), catalog)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "overrides.json")
+	if err := os.WriteFile(file, []byte(testOverrides), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := LoadOverrides(file, probe.DefaultCatalog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(overrides["aws_dynamodb_table"]) != 1 || len(overrides["aws_s3_bucket"]) != 1 {
+		t.Errorf("unexpected overrides %v", overrides)
+	}
+
+	if _, err := LoadOverrides(filepath.Join(t.TempDir(), "missing.json"), probe.DefaultCatalog()); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutTable(fakeaws.Table{Name: "live"})
+
+	overrides, err := ParseOverrides([]byte(testOverrides), probe.DefaultCatalog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("falls through to AWS", func(t *testing.T) {
+		registry := probe.NewProberRegistryWithCatalog(cfg, ApplyOverrides(probe.DefaultCatalog(), overrides, false))
+
+		prober, err := registry.GetProber("AWS::DynamoDB::Table")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sp, ok := prober.(probe.ServiceProber); !ok || sp.Service() != "dynamodb" {
+			t.Errorf("expected the wrapped prober's service, got %v", prober)
+		}
+
+		result, err := prober.Probe(context.Background(), "orders")
+		if err != nil || result.Status != "ACTIVE" {
+			t.Fatalf("expected the override, got %+v, %v", result, err)
+		}
+		if calls := server.CallCount(fakeaws.OpDescribeTable); calls != 0 {
+			t.Errorf("expected no calls for an overridden table, got %d", calls)
+		}
+
+		result, err = prober.Probe(context.Background(), "live")
+		if err != nil || !result.Exists {
+			t.Fatalf("expected the live table, got %+v, %v", result, err)
+		}
+		if calls := server.CallCount(fakeaws.OpDescribeTable); calls != 1 {
+			t.Errorf("expected one DescribeTable call, got %d", calls)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		registry := probe.NewProberRegistryWithCatalog(cfg, ApplyOverrides(probe.DefaultCatalog(), overrides, true))
+
+		prober, _ := registry.GetProber("aws_dynamodb_table")
+		if _, err := prober.Probe(context.Background(), "live"); err == nil || !strings.Contains(err.Error(), "no override for aws_dynamodb_table/live") {
+			t.Fatalf("expected a strict mode error, got %v", err)
+		}
+
+		// Types without any overrides are covered by strict mode too
+		prober, _ = registry.GetProber("aws_s3_bucket")
+		if _, err := prober.Probe(context.Background(), "assets"); err == nil {
+			t.Fatal("expected a strict mode error")
+		}
+		if server.CallCount(fakeaws.OpDescribeTable) != 1 {
+			t.Error("strict mode should make no AWS calls")
+		}
+	})
+
+	// The catalog overrides were applied to is left alone
+	prober, _ := probe.NewProberRegistry(cfg).GetProber("aws_dynamodb_table")
+	if result, err := prober.Probe(context.Background(), "orders"); err != nil || result.Exists {
+		t.Fatalf("expected the unwrapped prober to call AWS, got %+v, %v", result, err)
+	}
+}
+
+func TestOverrideProber_Concurrent(t *testing.T) {
+	var builds atomic.Int32
+	prober := &overrideProber{
+		canonicalType: "aws_example_widget",
+		inner: func() probe.ResourceProber {
+			builds.Add(1)
+			return &sequenceProber{}
+		},
+	}
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prober.wrapped()
+		}()
+	}
+	wg.Wait()
+
+	if got := builds.Load(); got != 1 {
+		t.Errorf("built the wrapped prober %d times, want once", got)
+	}
+}
+
+// configureProvider runs p.Configure with the given configuration values;
+// unset attributes are null.
+func configureProvider(t *testing.T, p *ProbeProvider, values map[string]tftypes.Value) (*ProbeProviderData, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	req := provider.ConfigureRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, values)},
+	}
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, req, &resp)
+
+	providerData, _ := resp.DataSourceData.(*ProbeProviderData)
+	return providerData, resp.Diagnostics
+}
+
+func TestProbeProvider_ConfigureOverrides(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	overrideType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"aws_dynamodb_table/orders": tftypes.Object{AttributeTypes: map[string]tftypes.Type{"status": tftypes.String}},
+	}}
+	inline := tftypes.NewValue(overrideType, map[string]tftypes.Value{
+		"aws_dynamodb_table/orders": tftypes.NewValue(overrideType.AttributeTypes["aws_dynamodb_table/orders"], map[string]tftypes.Value{
+			"status": tftypes.NewValue(tftypes.String, "ACTIVE"),
+		}),
+	})
+
+	t.Run("inline and strict", func(t *testing.T) {
+		providerData, diags := configureProvider(t, New("test")().(*ProbeProvider), map[string]tftypes.Value{
+			"overrides":        inline,
+			"overrides_strict": tftypes.NewValue(tftypes.Bool, true),
+		})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !providerData.OverridesStrict || providerData.Emulator != nil {
+			t.Errorf("expected strict mode without an emulator, got %+v", providerData)
+		}
+
+		d := &ProbeDataSource{registry: probe.NewProberRegistryWithCatalog(providerData.Config, providerData.Catalog)}
+		model, diags := readProbe(t, d, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+			"id":   tftypes.NewValue(tftypes.String, "orders"),
+		})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !model.Exists.ValueBool() || model.Status.ValueString() != "ACTIVE" || model.ImportID.ValueString() != "orders" {
+			t.Errorf("unexpected model %+v", model)
+		}
+	})
+
+	t.Run("conflicts with overrides_file", func(t *testing.T) {
+		_, diags := configureProvider(t, New("test")().(*ProbeProvider), map[string]tftypes.Value{
+			"overrides":      inline,
+			"overrides_file": tftypes.NewValue(tftypes.String, "overrides.json"),
+		})
+		if !diags.HasError() || diags[0].Summary() != "Conflicting overrides" {
+			t.Fatalf("expected a conflict, got %v", diags)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "overrides.json")
+		if err := os.WriteFile(file, []byte(`{"aws_nope/x": {}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, diags := configureProvider(t, New("test")().(*ProbeProvider), map[string]tftypes.Value{
+			"overrides_file":   tftypes.NewValue(tftypes.String, file),
+			"overrides_strict": tftypes.NewValue(tftypes.Bool, true),
+		})
+		if !diags.HasError() || diags[0].Summary() != "Invalid overrides" {
+			t.Fatalf("expected invalid overrides, got %v", diags)
+		}
+	})
+}
+
+func TestTftypesToGo(t *testing.T) {
+	value := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":  tftypes.String,
+		"count": tftypes.Number,
+		"on":    tftypes.Bool,
+		"list":  tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Number}},
+		"tags":  tftypes.Map{ElementType: tftypes.String},
+		"none":  tftypes.String,
+	}}, map[string]tftypes.Value{
+		"name":  tftypes.NewValue(tftypes.String, "orders"),
+		"count": tftypes.NewValue(tftypes.Number, 3),
+		"on":    tftypes.NewValue(tftypes.Bool, true),
+		"list": tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Number}}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "a"),
+			tftypes.NewValue(tftypes.Number, 1.5),
+		}),
+		"tags": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"Team": tftypes.NewValue(tftypes.String, "payments"),
+		}),
+		"none": tftypes.NewValue(tftypes.String, nil),
+	})
+
+	got, err := tftypesToGo(value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj := got.(map[string]any)
+	if obj["name"] != "orders" || obj["count"] != 3.0 || obj["on"] != true || obj["none"] != nil {
+		t.Errorf("unexpected scalars %v", obj)
+	}
+	if list := obj["list"].([]any); list[0] != "a" || list[1] != 1.5 {
+		t.Errorf("unexpected list %v", list)
+	}
+	if tags := obj["tags"].(map[string]any); tags["Team"] != "payments" {
+		t.Errorf("unexpected map %v", tags)
+	}
+
+	if _, err := tftypesToGo(tftypes.NewValue(tftypes.String, tftypes.UnknownValue)); err == nil {
+		t.Error("expected an error for an unknown value")
+	}
+}
+
+func TestAccProbeDataSource_overrides(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProbeDataSourceConfig_overrides,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.probe.table", "exists", "true"),
+					resource.TestCheckResourceAttr("data.probe.table", "status", "ACTIVE"),
+					resource.TestCheckResourceAttr("data.probe.table", "properties.BillingMode", "PAY_PER_REQUEST"),
+					resource.TestCheckResourceAttr("data.probe.table", "import_id", "orders"),
+					resource.TestCheckResourceAttr("data.probe.bucket", "exists", "false"),
+				),
+			},
+			{
+				Config:      testAccProbeDataSourceConfig_overrides + testAccProbeDataSourceConfig_notOverridden,
+				ExpectError: regexp.MustCompile(`no override for aws_s3_bucket/assets`),
+			},
+		},
+	})
+}
+
+const testAccProbeDataSourceConfig_overrides = `
+provider "probe" {
+  overrides_strict = true
+  overrides = {
+    "aws_dynamodb_table/orders" = {
+      status     = "ACTIVE"
+      properties = { BillingMode = "PAY_PER_REQUEST" }
+    }
+    "aws_s3_bucket/gone" = { exists = false }
+  }
+}
+
+data "probe" "table" {
+  type = "AWS::DynamoDB::Table"
+  id   = "orders"
+}
+
+data "probe" "bucket" {
+  type = "aws_s3_bucket"
+  id   = "gone"
+}
+`
+
+const testAccProbeDataSourceConfig_notOverridden = `
+data "probe" "assets" {
+  type = "aws_s3_bucket"
+  id   = "assets"
+}
+`
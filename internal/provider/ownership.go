@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ownership classes a resource falls into under an OwnershipRule.
+const (
+	// OwnershipOwned means the ownership tag has the expected value.
+	OwnershipOwned = "owned"
+
+	// OwnershipForeign means the ownership tag has a different value.
+	OwnershipForeign = "foreign"
+
+	// OwnershipUntagged means the resource lacks the ownership tag, or its
+	// prober doesn't collect tags.
+	OwnershipUntagged = "untagged"
+)
+
+// OwnershipRule identifies resources a configuration owns by a tag.
+type OwnershipRule struct {
+	TagKey        string
+	ExpectedValue string
+}
+
+// Classify returns the ownership class of a resource with tags.
+func (r OwnershipRule) Classify(tags map[string]string) string {
+	value, ok := tags[r.TagKey]
+	switch {
+	case !ok:
+		return OwnershipUntagged
+	case value == r.ExpectedValue:
+		return OwnershipOwned
+	default:
+		return OwnershipForeign
+	}
+}
+
+// OwnershipModel describes an ownership block.
+type OwnershipModel struct {
+	TagKey        types.String `tfsdk:"tag_key"`
+	ExpectedValue types.String `tfsdk:"expected_value"`
+}
+
+// merge returns the rule m describes, taking unset fields from fallback. It
+// returns false if the rule is incomplete: it has no tag_key, or no
+// expected_value to compare the tag with.
+func (m *OwnershipModel) merge(fallback *OwnershipRule) (OwnershipRule, bool) {
+	var rule OwnershipRule
+	hasValue := fallback != nil
+	if fallback != nil {
+		rule = *fallback
+	}
+	if m != nil {
+		if !m.TagKey.IsNull() {
+			rule.TagKey = m.TagKey.ValueString()
+		}
+		if !m.ExpectedValue.IsNull() {
+			rule.ExpectedValue = m.ExpectedValue.ValueString()
+			hasValue = true
+		}
+	}
+	return rule, rule.TagKey != "" && hasValue
+}
+
+// validateOwnership checks that a probe's ownership block, together with the
+// provider's rule fallback, names both a tag and its expected value. Unknown
+// attributes are checked once they're known.
+func validateOwnership(m *OwnershipModel, fallback *OwnershipRule) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if m == nil || m.TagKey.IsUnknown() || m.ExpectedValue.IsUnknown() {
+		return diags
+	}
+
+	rule, ok := m.merge(fallback)
+	if ok {
+		return diags
+	}
+	attr := "expected_value"
+	if rule.TagKey == "" {
+		attr = "tag_key"
+	}
+	diags.AddAttributeError(
+		path.Root("ownership").AtName(attr),
+		"Incomplete ownership rule",
+		fmt.Sprintf("Set %s here or in the provider's ownership block.", attr),
+	)
+	return diags
+}
+
+// providerOwnershipBlock is the provider's ownership block.
+func providerOwnershipBlock() schema.Block {
+	return schema.SingleNestedBlock{
+		Description: "Tag convention that marks resources this configuration owns. Probes report owned, foreign and untagged against it.",
+		Attributes: map[string]schema.Attribute{
+			"tag_key": schema.StringAttribute{
+				Description: "Tag that records the owner (e.g., ManagedBy).",
+				Optional:    true,
+			},
+			"expected_value": schema.StringAttribute{
+				Description: "Value of tag_key on resources this configuration owns.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// probeOwnershipBlock is the ownership block of the probe data source. Its
+// attributes override the provider's.
+func probeOwnershipBlock() dsschema.Block {
+	return dsschema.SingleNestedBlock{
+		Description: "Ownership tag convention for this probe. Unset attributes fall back to the provider's ownership block.",
+		Attributes: map[string]dsschema.Attribute{
+			"tag_key": dsschema.StringAttribute{
+				Description: "Tag that records the owner (e.g., ManagedBy).",
+				Optional:    true,
+			},
+			"expected_value": dsschema.StringAttribute{
+				Description: "Value of tag_key on resources this configuration owns.",
+				Optional:    true,
+			},
+		},
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+func TestOwnershipRule_Classify(t *testing.T) {
+	rule := OwnershipRule{TagKey: "ManagedBy", ExpectedValue: "payments"}
+
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		expected string
+	}{
+		{name: "owned", tags: map[string]string{"ManagedBy": "payments"}, expected: OwnershipOwned},
+		{name: "foreign", tags: map[string]string{"ManagedBy": "search"}, expected: OwnershipForeign},
+		{name: "empty value is foreign", tags: map[string]string{"ManagedBy": ""}, expected: OwnershipForeign},
+		{name: "untagged", tags: map[string]string{"Team": "payments"}, expected: OwnershipUntagged},
+		{name: "no tags", expected: OwnershipUntagged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rule.Classify(tt.tags); got != tt.expected {
+				t.Errorf("Classify(%v) = %q, want %q", tt.tags, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOwnershipModel_Merge(t *testing.T) {
+	fallback := &OwnershipRule{TagKey: "ManagedBy", ExpectedValue: "payments"}
+
+	var none *OwnershipModel
+	if rule, ok := none.merge(fallback); !ok || rule != *fallback {
+		t.Errorf("expected the provider rule, got %+v, %t", rule, ok)
+	}
+	if _, ok := none.merge(nil); ok {
+		t.Error("expected no rule without a block or provider rule")
+	}
+
+	override := &OwnershipModel{TagKey: types.StringNull(), ExpectedValue: types.StringValue("search")}
+	if rule, ok := override.merge(fallback); !ok || rule.TagKey != "ManagedBy" || rule.ExpectedValue != "search" {
+		t.Errorf("expected expected_value to be overridden, got %+v", rule)
+	}
+	if _, ok := override.merge(nil); ok {
+		t.Error("expected a rule without tag_key to be incomplete")
+	}
+
+	keyOnly := &OwnershipModel{TagKey: types.StringValue("Team"), ExpectedValue: types.StringNull()}
+	if rule, ok := keyOnly.merge(fallback); !ok || rule.TagKey != "Team" || rule.ExpectedValue != "payments" {
+		t.Errorf("expected tag_key to be overridden, got %+v", rule)
+	}
+	if _, ok := keyOnly.merge(nil); ok {
+		t.Error("expected a rule without expected_value to be incomplete")
+	}
+}
+
+func TestProbeDataSource_ValidateOwnership(t *testing.T) {
+	ctx := context.Background()
+	cfg := aws.Config{Region: "us-east-1"}
+
+	ownershipType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"tag_key":        tftypes.String,
+		"expected_value": tftypes.String,
+	}}
+	validate := func(d *ProbeDataSource, tagKey, expectedValue any) diag.Diagnostics {
+		var schemaResp datasource.SchemaResponse
+		d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+		objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+		var resp datasource.ValidateConfigResponse
+		d.ValidateConfig(ctx, datasource.ValidateConfigRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, map[string]tftypes.Value{
+				"type": tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+				"id":   tftypes.NewValue(tftypes.String, "orders"),
+				"ownership": tftypes.NewValue(ownershipType, map[string]tftypes.Value{
+					"tag_key":        tftypes.NewValue(tftypes.String, tagKey),
+					"expected_value": tftypes.NewValue(tftypes.String, expectedValue),
+				}),
+			})},
+		}, &resp)
+		return resp.Diagnostics
+	}
+
+	registry := probe.NewProberRegistry(cfg)
+	withRule := &ProbeDataSource{registry: registry, ownership: &OwnershipRule{TagKey: "ManagedBy", ExpectedValue: "payments"}}
+	withoutRule := &ProbeDataSource{registry: registry}
+
+	tests := []struct {
+		name          string
+		d             *ProbeDataSource
+		tagKey        any
+		expectedValue any
+		wantAttr      string
+	}{
+		{name: "complete", d: withoutRule, tagKey: "Team", expectedValue: "payments"},
+		{name: "provider value", d: withRule, tagKey: "Team"},
+		{name: "provider key", d: withRule, expectedValue: "search"},
+		{name: "missing value", d: withoutRule, tagKey: "Team", wantAttr: "expected_value"},
+		{name: "missing key", d: withoutRule, expectedValue: "search", wantAttr: "tag_key"},
+		{name: "unknown value", d: withoutRule, tagKey: "Team", expectedValue: tftypes.UnknownValue},
+		{name: "unconfigured", d: &ProbeDataSource{}, tagKey: "Team"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validate(tt.d, tt.tagKey, tt.expectedValue)
+			if tt.wantAttr == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || diags[0].Summary() != "Incomplete ownership rule" {
+				t.Fatalf("expected an incomplete rule, got %v", diags)
+			}
+			withPath, ok := diags[0].(diag.DiagnosticWithPath)
+			if !ok || !withPath.Path().Equal(path.Root("ownership").AtName(tt.wantAttr)) {
+				t.Errorf("expected the error at ownership.%s, got %v", tt.wantAttr, diags[0])
+			}
+		})
+	}
+}
+
+func TestProbeDataSource_Ownership(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutTable(fakeaws.Table{Name: "orders", Tags: map[string]string{"ManagedBy": "payments"}})
+	server.PutTable(fakeaws.Table{Name: "search", Tags: map[string]string{"ManagedBy": "search"}})
+	server.PutTable(fakeaws.Table{Name: "legacy"})
+
+	d := &ProbeDataSource{
+		cfg:       cfg,
+		registry:  probe.NewProberRegistry(cfg),
+		ownership: &OwnershipRule{TagKey: "ManagedBy", ExpectedValue: "payments"},
+	}
+
+	ownershipType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"tag_key":        tftypes.String,
+		"expected_value": tftypes.String,
+	}}
+	probeValues := func(id string, ownership tftypes.Value) map[string]tftypes.Value {
+		values := map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+			"id":   tftypes.NewValue(tftypes.String, id),
+		}
+		if ownership.Type() != nil {
+			values["ownership"] = ownership
+		}
+		return values
+	}
+
+	tests := []struct {
+		name      string
+		id        string
+		ownership tftypes.Value
+		owned     bool
+		foreign   bool
+		untagged  bool
+	}{
+		{name: "owned", id: "orders", owned: true},
+		{name: "foreign", id: "search", foreign: true},
+		{name: "untagged", id: "legacy", untagged: true},
+		{name: "missing", id: "invoices"},
+		{
+			name: "per-probe expected value",
+			id:   "search",
+			ownership: tftypes.NewValue(ownershipType, map[string]tftypes.Value{
+				"tag_key":        tftypes.NewValue(tftypes.String, nil),
+				"expected_value": tftypes.NewValue(tftypes.String, "search"),
+			}),
+			owned: true,
+		},
+		{
+			name: "per-probe tag key",
+			id:   "orders",
+			ownership: tftypes.NewValue(ownershipType, map[string]tftypes.Value{
+				"tag_key":        tftypes.NewValue(tftypes.String, "Team"),
+				"expected_value": tftypes.NewValue(tftypes.String, nil),
+			}),
+			untagged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, diags := readProbe(t, d, probeValues(tt.id, tt.ownership))
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if model.Owned.ValueBool() != tt.owned || model.Foreign.ValueBool() != tt.foreign || model.Untagged.ValueBool() != tt.untagged {
+				t.Errorf("got owned=%v foreign=%v untagged=%v", model.Owned, model.Foreign, model.Untagged)
+			}
+			if model.Owned.IsNull() {
+				t.Error("expected ownership attributes to be set with a rule")
+			}
+		})
+	}
+
+	t.Run("no rule", func(t *testing.T) {
+		model, diags := readProbe(t, &ProbeDataSource{cfg: cfg, registry: probe.NewProberRegistry(cfg)}, probeValues("orders", tftypes.Value{}))
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !model.Owned.IsNull() || !model.Foreign.IsNull() || !model.Untagged.IsNull() {
+			t.Errorf("expected null ownership attributes, got %+v", model)
+		}
+	})
+
+	t.Run("incomplete rule", func(t *testing.T) {
+		_, diags := readProbe(t, &ProbeDataSource{cfg: cfg, registry: probe.NewProberRegistry(cfg)}, probeValues("orders",
+			tftypes.NewValue(ownershipType, map[string]tftypes.Value{
+				"tag_key":        tftypes.NewValue(tftypes.String, nil),
+				"expected_value": tftypes.NewValue(tftypes.String, "payments"),
+			})))
+		if !diags.HasError() || diags[0].Summary() != "Incomplete ownership rule" {
+			t.Fatalf("expected an incomplete rule, got %v", diags)
+		}
+	})
+}
+
+func TestProbeProvider_ConfigureOwnership(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	ownershipType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"tag_key":        tftypes.String,
+		"expected_value": tftypes.String,
+	}}
+	configure := func(tagKey, expectedValue any) (*ProbeProviderData, diag.Diagnostics) {
+		return configureProvider(t, New("test")().(*ProbeProvider), map[string]tftypes.Value{
+			"localstack": tftypes.NewValue(tftypes.Bool, false),
+			"ownership": tftypes.NewValue(ownershipType, map[string]tftypes.Value{
+				"tag_key":        tftypes.NewValue(tftypes.String, tagKey),
+				"expected_value": tftypes.NewValue(tftypes.String, expectedValue),
+			}),
+		})
+	}
+
+	providerData, diags := configure("ManagedBy", "payments")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if providerData.Ownership == nil || *providerData.Ownership != (OwnershipRule{TagKey: "ManagedBy", ExpectedValue: "payments"}) {
+		t.Errorf("unexpected ownership rule %+v", providerData.Ownership)
+	}
+
+	if _, diags := configure("ManagedBy", nil); !diags.HasError() || diags[0].Summary() != "Incomplete ownership rule" {
+		t.Fatalf("expected an incomplete rule, got %v", diags)
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// Ensure ProbeAdoptionResource satisfies various resource interfaces.
+var _ resource.Resource = &ProbeAdoptionResource{}
+var _ resource.ResourceWithConfigure = &ProbeAdoptionResource{}
+var _ resource.ResourceWithModifyPlan = &ProbeAdoptionResource{}
+var _ resource.ResourceWithImportState = &ProbeAdoptionResource{}
+var _ resource.ResourceWithValidateConfig = &ProbeAdoptionResource{}
+
+// ProbeAdoptionResource records whether a resource existed before the
+// configuration first managed it. The target is probed once, when the
+// adoption is planned; the decision then stays in state until the adoption
+// is replaced.
+type ProbeAdoptionResource struct {
+	registry *probe.ProberRegistry
+	emulator *EmulatorInfo
+	clients  *ClientPool
+
+	// hints describes the provider before it's configured.
+	hints *providerHints
+}
+
+// ProbeAdoptionResourceModel describes the resource data model.
+type ProbeAdoptionResourceModel struct {
+	Type          types.String `tfsdk:"type"`
+	ID            types.String `tfsdk:"id"`
+	ExistsIf      types.List   `tfsdk:"exists_if"`
+	PreExisted    types.Bool   `tfsdk:"pre_existed"`
+	Arn           types.String `tfsdk:"arn"`
+	TerraformType types.String `tfsdk:"terraform_type"`
+	ImportID      types.String `tfsdk:"import_id"`
+}
+
+func NewProbeAdoptionResource() resource.Resource {
+	return &ProbeAdoptionResource{}
+}
+
+func (r *ProbeAdoptionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_adoption"
+}
+
+func (r *ProbeAdoptionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Records whether an AWS resource already existed when it was first planned. " +
+			"Unlike the probe data source, the decision doesn't change once the configuration creates the resource itself.",
+
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Description: "Resource type (e.g., aws_dynamodb_table or AWS::DynamoDB::Table). Changing it probes again.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Description: "Resource identifier (table name, bucket name, etc.). Changing it probes again.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"exists_if": schema.ListAttribute{
+				Description: "Lifecycle states in which the resource counts as pre-existing: " + strings.Join(probe.LifecycleStates(), ", ") + ". A resource in another state, such as a table being deleted, is recorded as not pre-existing. Must not be empty. Defaults to every state. Changing it probes again.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"pre_existed": schema.BoolAttribute{
+				Description: "Whether the resource existed when the adoption was planned. Known at plan time when type and id are.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"arn": schema.StringAttribute{
+				Description: "ARN of the pre-existing resource (null if it did not pre-exist).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"terraform_type": schema.StringAttribute{
+				Description: "The hashicorp/aws resource type that manages the pre-existing resource (null if it did not pre-exist).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"import_id": schema.StringAttribute{
+				Description: "Identifier to use in an import block for terraform_type (null if the resource did not pre-exist).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ProbeAdoptionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProbeProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProbeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	// Without a known provider configuration, plans leave probe results
+	// unknown until apply
+	if len(providerData.UnknownConfig) > 0 {
+		return
+	}
+
+	r.registry = providerData.Registry
+	r.emulator = providerData.Emulator
+	r.clients = providerData.Clients
+}
+
+// ValidateConfig reports unsupported types, malformed identifiers and
+// invalid lifecycle states before any probe runs.
+func (r *ProbeAdoptionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ProbeAdoptionResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateTarget(r.registry, r.hints, data.Type, data.ID)...)
+	resp.Diagnostics.Append(validateLifecycleStates(ctx, data.ExistsIf)...)
+}
+
+// ModifyPlan probes the target when an adoption is created, so pre_existed
+// is known at plan time and can drive count or for_each.
+func (r *ProbeAdoptionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Only new adoptions are probed; destroys and existing adoptions keep
+	// their decision.
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() || r.registry == nil {
+		return
+	}
+
+	var plan ProbeAdoptionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Type.IsUnknown() || plan.ID.IsUnknown() || plan.ExistsIf.IsUnknown() ||
+		slices.ContainsFunc(plan.ExistsIf.Elements(), attr.Value.IsUnknown) {
+		// Probed during apply instead
+		return
+	}
+
+	resp.Diagnostics.Append(r.decide(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *ProbeAdoptionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ProbeAdoptionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The decision made at plan time stands, so apply matches the plan
+	if data.PreExisted.IsUnknown() {
+		resp.Diagnostics.Append(r.decide(ctx, &data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read keeps the recorded decision; it never probes again.
+func (r *ProbeAdoptionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ProbeAdoptionResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only happens when nothing changes, since every argument forces
+// replacement.
+func (r *ProbeAdoptionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ProbeAdoptionResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete forgets the decision. The target resource is left alone.
+func (r *ProbeAdoptionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// ImportState accepts "type/id" and records whether the resource exists now,
+// in any lifecycle state.
+func (r *ProbeAdoptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resourceType, identifier, ok := strings.Cut(req.ID, "/")
+	if !ok || resourceType == "" || identifier == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected an import ID of the form type/id (e.g., aws_dynamodb_table/orders), got %q.", req.ID),
+		)
+		return
+	}
+
+	data := ProbeAdoptionResourceModel{
+		Type:     types.StringValue(resourceType),
+		ID:       types.StringValue(identifier),
+		ExistsIf: types.ListNull(types.StringType),
+	}
+	resp.Diagnostics.Append(r.decide(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// decide probes the target of data and records the result.
+func (r *ProbeAdoptionResource) decide(ctx context.Context, data *ProbeAdoptionResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.registry == nil {
+		diags.AddAttributeError(
+			path.Root("pre_existed"),
+			"Provider Not Configured",
+			"The probe provider must be configured before an adoption can be decided.",
+		)
+		return diags
+	}
+
+	result, diags := runProbe(withClientPool(ctx, r.clients), r.registry, r.emulator, data.Type.ValueString(), data.ID.ValueString())
+	if diags.HasError() {
+		return diags
+	}
+
+	// A resource outside the exists_if states, such as a table being
+	// deleted, didn't pre-exist
+	var existsIf []string
+	diags.Append(data.ExistsIf.ElementsAs(ctx, &existsIf, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	result = result.ExistsIn(existsIf)
+
+	data.PreExisted = types.BoolValue(result.Exists)
+	if result.Exists {
+		data.Arn = stringOrNull(result.Arn)
+		data.TerraformType = stringOrNull(result.TerraformType)
+		data.ImportID = stringOrNull(result.ImportID)
+	} else {
+		data.Arn = types.StringNull()
+		data.TerraformType = types.StringNull()
+		data.ImportID = types.StringNull()
+	}
+	return diags
+}
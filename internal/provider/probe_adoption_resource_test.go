@@ -0,0 +1,320 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// planAdoption runs r.ModifyPlan and r.Create for a new adoption of the
+// given type and id, and returns the planned and created models. Create is
+// skipped while id or exists_if is unknown, since they are known by apply
+// time.
+func planAdoption(t *testing.T, r *ProbeAdoptionResource, values map[string]tftypes.Value) (planned, created ProbeAdoptionResourceModel, diags diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	// Unset computed attributes are unknown in a proposed new state
+	proposed := map[string]tftypes.Value{}
+	for name, typ := range objType.AttributeTypes {
+		if schemaResp.Schema.Attributes[name].IsComputed() {
+			proposed[name] = tftypes.NewValue(typ, tftypes.UnknownValue)
+		}
+	}
+	for name, v := range values {
+		proposed[name] = v
+	}
+
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: objectValue(objType, proposed)}
+	modifyResp := resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, values)},
+		Plan:   plan,
+		State:  tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, nil)},
+	}, &modifyResp)
+	diags.Append(modifyResp.Diagnostics...)
+	if diags.HasError() {
+		return planned, created, diags
+	}
+	diags.Append(modifyResp.Plan.Get(ctx, &planned)...)
+	if planned.ID.IsUnknown() || planned.ExistsIf.IsUnknown() {
+		return planned, created, diags
+	}
+
+	createResp := resource.CreateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, nil)},
+	}
+	r.Create(ctx, resource.CreateRequest{Plan: modifyResp.Plan}, &createResp)
+	diags.Append(createResp.Diagnostics...)
+	if !diags.HasError() {
+		diags.Append(createResp.State.Get(ctx, &created)...)
+	}
+	return planned, created, diags
+}
+
+func TestProbeAdoptionResource_Plan(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutTable(fakeaws.Table{Name: "orders"})
+	server.PutTable(fakeaws.Table{Name: "legacy", Status: "DELETING"})
+	r := &ProbeAdoptionResource{registry: probe.NewProberRegistry(cfg)}
+
+	t.Run("pre-existing", func(t *testing.T) {
+		planned, created, diags := planAdoption(t, r, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "AWS::DynamoDB::Table"),
+			"id":   tftypes.NewValue(tftypes.String, "orders"),
+		})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !planned.PreExisted.ValueBool() {
+			t.Fatalf("expected pre_existed to be known and true at plan time, got %v", planned.PreExisted)
+		}
+		if planned.ImportID.ValueString() != "orders" || planned.TerraformType.ValueString() != "aws_dynamodb_table" {
+			t.Errorf("unexpected import target %v/%v", planned.TerraformType, planned.ImportID)
+		}
+		if !reflect.DeepEqual(created, planned) {
+			t.Errorf("expected Create to keep the planned decision, got %+v", created)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		planned, created, diags := planAdoption(t, r, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+			"id":   tftypes.NewValue(tftypes.String, "invoices"),
+		})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if planned.PreExisted.IsUnknown() || planned.PreExisted.ValueBool() {
+			t.Fatalf("expected pre_existed = false at plan time, got %v", planned.PreExisted)
+		}
+		if !created.Arn.IsNull() || !created.ImportID.IsNull() {
+			t.Errorf("expected null arn and import_id, got %+v", created)
+		}
+	})
+
+	t.Run("exists_if", func(t *testing.T) {
+		activeOnly := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, probe.LifecycleActive),
+		})
+		tests := []struct {
+			name     string
+			id       string
+			existsIf tftypes.Value
+			want     bool
+		}{
+			{name: "deleting excluded", id: "legacy", existsIf: activeOnly, want: false},
+			{name: "deleting by default", id: "legacy", existsIf: tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil), want: true},
+			{name: "active", id: "orders", existsIf: activeOnly, want: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				planned, created, diags := planAdoption(t, r, map[string]tftypes.Value{
+					"type":      tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+					"id":        tftypes.NewValue(tftypes.String, tt.id),
+					"exists_if": tt.existsIf,
+				})
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", diags)
+				}
+				if planned.PreExisted.IsUnknown() || planned.PreExisted.ValueBool() != tt.want {
+					t.Fatalf("expected pre_existed = %v at plan time, got %v", tt.want, planned.PreExisted)
+				}
+				if created.ImportID.IsNull() == tt.want {
+					t.Errorf("expected import_id only when pre-existing, got %v", created.ImportID)
+				}
+			})
+		}
+	})
+
+	t.Run("exists_if unknown until apply", func(t *testing.T) {
+		planned, _, diags := planAdoption(t, r, map[string]tftypes.Value{
+			"type":      tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+			"id":        tftypes.NewValue(tftypes.String, "legacy"),
+			"exists_if": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+		})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !planned.PreExisted.IsUnknown() {
+			t.Errorf("expected pre_existed to stay unknown, got %v", planned.PreExisted)
+		}
+	})
+
+	t.Run("id unknown until apply", func(t *testing.T) {
+		planned, _, diags := planAdoption(t, r, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+			"id":   tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !planned.PreExisted.IsUnknown() {
+			t.Errorf("expected pre_existed to stay unknown, got %v", planned.PreExisted)
+		}
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, _, diags := planAdoption(t, r, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "aws_nope"),
+			"id":   tftypes.NewValue(tftypes.String, "x"),
+		})
+		if !diags.HasError() || diags[0].Summary() != "Unsupported Resource Type" {
+			t.Fatalf("expected unsupported type, got %v", diags)
+		}
+	})
+}
+
+func TestProbeAdoptionResource_ValidateConfig(t *testing.T) {
+	_, cfg := getFakeAWSConfig(t)
+	r := &ProbeAdoptionResource{registry: probe.NewProberRegistry(cfg)}
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	tests := []struct {
+		name       string
+		existsIf   []string
+		wantPath   path.Path
+		wantDetail string
+	}{
+		{name: "valid", existsIf: []string{"active"}},
+		{name: "empty", existsIf: []string{}, wantPath: path.Root("exists_if"), wantDetail: "at least one lifecycle state"},
+		{name: "invalid", existsIf: []string{"gone"}, wantPath: path.Root("exists_if").AtListIndex(0), wantDetail: `"gone" is not a lifecycle state`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := make([]tftypes.Value, len(tt.existsIf))
+			for i, state := range tt.existsIf {
+				states[i] = tftypes.NewValue(tftypes.String, state)
+			}
+			var resp resource.ValidateConfigResponse
+			r.ValidateConfig(ctx, resource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, map[string]tftypes.Value{
+					"type":      tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+					"id":        tftypes.NewValue(tftypes.String, "orders"),
+					"exists_if": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, states),
+				})},
+			}, &resp)
+
+			if tt.wantDetail == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			errs := resp.Diagnostics.Errors()
+			if len(errs) != 1 {
+				t.Fatalf("expected one error, got %v", resp.Diagnostics)
+			}
+			withPath, ok := errs[0].(diag.DiagnosticWithPath)
+			if !ok || !withPath.Path().Equal(tt.wantPath) {
+				t.Errorf("expected error at %s, got %v", tt.wantPath, errs[0])
+			}
+			if !strings.Contains(errs[0].Detail(), tt.wantDetail) {
+				t.Errorf("expected detail containing %q, got %q", tt.wantDetail, errs[0].Detail())
+			}
+		})
+	}
+}
+
+func TestProbeAdoptionResource_ImportState(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutTable(fakeaws.Table{Name: "orders"})
+	r := &ProbeAdoptionResource{registry: probe.NewProberRegistry(cfg)}
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	importState := func(id string) (ProbeAdoptionResourceModel, diag.Diagnostics) {
+		resp := resource.ImportStateResponse{
+			State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, nil)},
+		}
+		r.ImportState(ctx, resource.ImportStateRequest{ID: id}, &resp)
+		var data ProbeAdoptionResourceModel
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+		}
+		return data, resp.Diagnostics
+	}
+
+	data, diags := importState("aws_dynamodb_table/orders")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if data.Type.ValueString() != "aws_dynamodb_table" || data.ID.ValueString() != "orders" || !data.PreExisted.ValueBool() {
+		t.Errorf("unexpected imported state %+v", data)
+	}
+
+	if _, diags := importState("orders"); !diags.HasError() || diags[0].Summary() != "Invalid Import ID" {
+		t.Fatalf("expected an invalid import ID, got %v", diags)
+	}
+}
+
+func TestAccProbeAdoptionResource(t *testing.T) {
+	server := testAccFakeAWS(t)
+	server.PutTable(fakeaws.Table{Name: "orders"})
+
+	tfresource.Test(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccProbeAdoptionResourceConfig,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("probe_adoption.orders", "pre_existed", "true"),
+					tfresource.TestCheckResourceAttr("probe_adoption.orders", "import_id", "orders"),
+					tfresource.TestCheckResourceAttr("probe_adoption.invoices", "pre_existed", "false"),
+				),
+			},
+			{
+				// Creating the table doesn't flip the recorded decision
+				PreConfig: func() { server.PutTable(fakeaws.Table{Name: "invoices"}) },
+				Config:    server.ProviderConfig() + testAccProbeAdoptionResourceConfig,
+				Check:     tfresource.TestCheckResourceAttr("probe_adoption.invoices", "pre_existed", "false"),
+			},
+			{
+				ResourceName:      "probe_adoption.orders",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return "aws_dynamodb_table/orders", nil
+				},
+			},
+		},
+	})
+}
+
+const testAccProbeAdoptionResourceConfig = `
+resource "probe_adoption" "orders" {
+  type = "aws_dynamodb_table"
+  id   = "orders"
+}
+
+resource "probe_adoption" "invoices" {
+  type = "aws_dynamodb_table"
+  id   = "invoices"
+}
+`
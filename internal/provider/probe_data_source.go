@@ -4,34 +4,87 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// Formats the probe data source can report properties in.
+const (
+	// PropertiesFormatDynamic sets both properties and properties_json.
+	PropertiesFormatDynamic = "dynamic"
+
+	// PropertiesFormatJSON sets only properties_json, leaving properties
+	// null so its type never changes.
+	PropertiesFormatJSON = "json"
 )
 
 // Ensure ProbeDataSource satisfies various datasource interfaces.
 var _ datasource.DataSource = &ProbeDataSource{}
 var _ datasource.DataSourceWithConfigure = &ProbeDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &ProbeDataSource{}
 
 // ProbeDataSource defines the data source implementation.
 type ProbeDataSource struct {
-	cfg      aws.Config
-	registry *ProberRegistry
+	cfg       aws.Config
+	registry  *probe.ProberRegistry
+	emulator  *EmulatorInfo
+	ownership *OwnershipRule
+
+	// clients caches the accounts ARNs are built with.
+	clients *ClientPool
+
+	// dropSensitive leaves sensitive properties out of results.
+	dropSensitive bool
+
+	// onError is the provider's on_error policy, or empty if it isn't set.
+	onError string
+
+	// unknownConfig lists the provider arguments that weren't known when
+	// the provider was configured.
+	unknownConfig []string
+
+	// hints describes the provider before it's configured.
+	hints *providerHints
 }
 
 // ProbeDataSourceModel describes the data source data model.
 type ProbeDataSourceModel struct {
-	Type       types.String  `tfsdk:"type"`
-	ID         types.String  `tfsdk:"id"`
-	Exists     types.Bool    `tfsdk:"exists"`
-	Arn        types.String  `tfsdk:"arn"`
-	Properties types.Dynamic `tfsdk:"properties"`
+	Type             types.String    `tfsdk:"type"`
+	ID               types.String    `tfsdk:"id"`
+	Exists           types.Bool      `tfsdk:"exists"`
+	Arn              types.String    `tfsdk:"arn"`
+	Properties       types.Dynamic   `tfsdk:"properties"`
+	PropertiesFormat types.String    `tfsdk:"properties_format"`
+	PropertiesJSON   types.String    `tfsdk:"properties_json"`
+	Sensitive        types.Dynamic   `tfsdk:"sensitive_properties"`
+	Status           types.String    `tfsdk:"status"`
+	LifecycleState   types.String    `tfsdk:"lifecycle_state"`
+	ExistsIf         types.List      `tfsdk:"exists_if"`
+	OnError          types.String    `tfsdk:"on_error"`
+	Error            types.String    `tfsdk:"error"`
+	TerraformType    types.String    `tfsdk:"terraform_type"`
+	ImportID         types.String    `tfsdk:"import_id"`
+	Ownership        *OwnershipModel `tfsdk:"ownership"`
+	Owned            types.Bool      `tfsdk:"owned"`
+	Foreign          types.Bool      `tfsdk:"foreign"`
+	Untagged         types.Bool      `tfsdk:"untagged"`
+	Desired          types.Dynamic   `tfsdk:"desired"`
+	Compatible       types.Bool      `tfsdk:"compatible"`
+	Conflicts        types.List      `tfsdk:"conflicts"`
 }
 
 func NewProbeDataSource() datasource.DataSource {
@@ -55,8 +108,29 @@ func (d *ProbeDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 				Description: "Resource identifier (table name, bucket name, etc.).",
 				Required:    true,
 			},
+			"desired": schema.DynamicAttribute{
+				Description: "Immutable properties the configuration intends the resource to have (e.g., KeySchema for a DynamoDB table). Compared with the existing resource to set compatible and conflicts.",
+				Optional:    true,
+			},
+			"properties_format": schema.StringAttribute{
+				Description: "How to report properties: dynamic (the default) sets properties and properties_json, json sets only properties_json.",
+				Optional:    true,
+			},
+			"exists_if": schema.ListAttribute{
+				Description: "Lifecycle states in which the resource counts as existing: " + strings.Join(probe.LifecycleStates(), ", ") + ". A resource in another state is reported as missing, apart from status and lifecycle_state. Must not be empty. Defaults to every state.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"on_error": schema.StringAttribute{
+				Description: "What to do when the probe fails unexpectedly, e.g. because AWS is throttling: " + strings.Join(OnErrorPolicies(), ", ") + ". Every policy but fail reports a warning, sets error and leaves the other attributes null, apart from exists: null for unknown, false for assume_missing and true for assume_present. Defaults to the provider's on_error.",
+				Optional:    true,
+			},
+			"error": schema.StringAttribute{
+				Description: "Error message if the probe failed and on_error let the read continue. Null if the probe succeeded.",
+				Computed:    true,
+			},
 			"exists": schema.BoolAttribute{
-				Description: "Whether the resource exists.",
+				Description: "Whether the resource exists in one of the exists_if states.",
 				Computed:    true,
 			},
 			"arn": schema.StringAttribute{
@@ -67,6 +141,56 @@ func (d *ProbeDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 				Description: "Resource properties as a map (null if resource does not exist).",
 				Computed:    true,
 			},
+			"properties_json": schema.StringAttribute{
+				Description: "Resource properties as JSON with sorted keys, stable across probes of an unchanged resource (null if resource does not exist).",
+				Computed:    true,
+			},
+			"sensitive_properties": schema.DynamicAttribute{
+				Description: "Properties the prober classifies as sensitive, such as policy documents, kept out of properties and properties_json (null if resource does not exist, it has none, or the provider sets drop_sensitive_properties).",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Resource status as the service reports it, e.g. ACTIVE (null if the resource was not found or has no status).",
+				Computed:    true,
+			},
+			"lifecycle_state": schema.StringAttribute{
+				Description: "Status normalized across services: " + strings.Join(probe.LifecycleStates(), ", ") + " (null if the resource was not found).",
+				Computed:    true,
+			},
+			"terraform_type": schema.StringAttribute{
+				Description: "The hashicorp/aws resource type that manages this resource (null if resource does not exist).",
+				Computed:    true,
+			},
+			"import_id": schema.StringAttribute{
+				Description: "Identifier to use in an import block for terraform_type (null if resource does not exist).",
+				Computed:    true,
+			},
+			"owned": schema.BoolAttribute{
+				Description: "Whether the resource exists and its ownership tag has the expected value (null without an ownership rule).",
+				Computed:    true,
+			},
+			"foreign": schema.BoolAttribute{
+				Description: "Whether the resource exists and its ownership tag has a different value (null without an ownership rule).",
+				Computed:    true,
+			},
+			"untagged": schema.BoolAttribute{
+				Description: "Whether the resource exists without the ownership tag (null without an ownership rule).",
+				Computed:    true,
+			},
+			"compatible": schema.BoolAttribute{
+				Description: "Whether the resource could be adopted without replacement: true if it doesn't exist or every desired property matches (null without desired).",
+				Computed:    true,
+			},
+			"conflicts": schema.ListAttribute{
+				Description: "Desired properties the existing resource has different values for (null without desired).",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"ownership": probeOwnershipBlock(),
 		},
 	}
 }
@@ -77,20 +201,119 @@ func (d *ProbeDataSource) Configure(ctx context.Context, req datasource.Configur
 		return
 	}
 
-	cfg, ok := req.ProviderData.(aws.Config)
+	providerData, ok := req.ProviderData.(*ProbeProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected aws.Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ProbeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.cfg = cfg
-	d.registry = NewProberRegistry(cfg)
+	if len(providerData.UnknownConfig) > 0 {
+		d.unknownConfig = providerData.UnknownConfig
+		return
+	}
+
+	d.cfg = providerData.Config
+	d.registry = providerData.Registry
+	d.emulator = providerData.Emulator
+	d.clients = providerData.Clients
+	d.ownership = providerData.Ownership
+	d.dropSensitive = providerData.DropSensitive
+	d.onError = providerData.OnError
+}
+
+// ValidateConfig reports unsupported types, malformed identifiers, unknown
+// properties formats, unknown lifecycle states, unknown on_error policies and
+// incomplete ownership rules before any probe runs.
+func (d *ProbeDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var resourceType, identifier, format, onError types.String
+	var existsIf types.List
+	var ownership *OwnershipModel
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &resourceType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("id"), &identifier)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("properties_format"), &format)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("exists_if"), &existsIf)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("on_error"), &onError)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ownership"), &ownership)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateTarget(d.registry, d.hints, resourceType, identifier)...)
+	resp.Diagnostics.Append(validatePropertiesFormat(format)...)
+	resp.Diagnostics.Append(validateLifecycleStates(ctx, existsIf)...)
+	resp.Diagnostics.Append(validateOnError(path.Root("on_error"), onError)...)
+	if d.registry != nil {
+		// The provider's ownership rule is only known once it's configured
+		resp.Diagnostics.Append(validateOwnership(ownership, d.ownership)...)
+	}
+}
+
+// validateLifecycleStates checks the exists_if argument. Unknown elements
+// are checked once they're known. An empty list is rejected, since no
+// resource could count as existing.
+func validateLifecycleStates(ctx context.Context, existsIf types.List) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if existsIf.IsNull() || existsIf.IsUnknown() {
+		return diags
+	}
+	if len(existsIf.Elements()) == 0 {
+		diags.AddAttributeError(
+			path.Root("exists_if"),
+			"Empty lifecycle states",
+			fmt.Sprintf("exists_if must list at least one lifecycle state, or be omitted to count every state. Use any of: %s.", strings.Join(probe.LifecycleStates(), ", ")),
+		)
+		return diags
+	}
+
+	for i, element := range existsIf.Elements() {
+		state, ok := element.(types.String)
+		if !ok || state.IsNull() || state.IsUnknown() {
+			continue
+		}
+		if !slices.Contains(probe.LifecycleStates(), state.ValueString()) {
+			diags.AddAttributeError(
+				path.Root("exists_if").AtListIndex(i),
+				"Invalid lifecycle state",
+				fmt.Sprintf("%q is not a lifecycle state. Use one of: %s.", state.ValueString(), strings.Join(probe.LifecycleStates(), ", ")),
+			)
+		}
+	}
+	return diags
+}
+
+// validatePropertiesFormat checks the properties_format argument.
+func validatePropertiesFormat(format types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if format.IsNull() || format.IsUnknown() {
+		return diags
+	}
+
+	switch format.ValueString() {
+	case PropertiesFormatDynamic, PropertiesFormatJSON:
+	default:
+		diags.AddAttributeError(
+			path.Root("properties_format"),
+			"Invalid properties format",
+			fmt.Sprintf("properties_format must be %q or %q, got %q.", PropertiesFormatDynamic, PropertiesFormatJSON, format.ValueString()),
+		)
+	}
+	return diags
 }
 
 func (d *ProbeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if !req.Config.Raw.IsFullyKnown() {
+		deferRead(ctx, req, resp)
+		return
+	}
+	if len(d.unknownConfig) > 0 {
+		resp.Diagnostics.Append(providerConfigUnknown(d.unknownConfig))
+		return
+	}
+
 	var data ProbeDataSourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -101,50 +324,328 @@ func (d *ProbeDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	resourceType := data.Type.ValueString()
 	identifier := data.ID.ValueString()
 
-	// Get the appropriate prober for this resource type
-	prober, err := d.registry.GetProber(resourceType)
+	resp.Diagnostics.Append(validateOwnership(data.Ownership, d.ownership)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	rule, hasRule := data.Ownership.merge(d.ownership)
+
+	desired, err := desiredProperties(ctx, data.Desired)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unsupported Resource Type",
-			fmt.Sprintf("Resource type %q is not supported. Supported types: %v", resourceType, d.registry.SupportedTypes()),
-		)
+		resp.Diagnostics.AddAttributeError(path.Root("desired"), "Invalid desired properties", err.Error())
 		return
 	}
 
-	// Probe the resource
-	result, err := prober.Probe(ctx, identifier)
+	prober, diags := resolveProber(d.registry, d.emulator, resourceType, identifier)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An unexpected failure, such as throttling, may continue with fallback
+	// values under on_error
+	result, err := prober.Probe(withClientPool(ctx, d.clients), identifier)
 	if err != nil {
-		resp.Diagnostics.AddError("Probe failed", err.Error())
+		resp.Diagnostics.Append(tolerateProbeError(&data, onErrorPolicy(data.OnError, d.onError), err)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
+	data.Error = types.StringNull()
+
+	// A resource outside the exists_if states, such as a table being
+	// deleted, is treated as missing
+	var existsIf []string
+	resp.Diagnostics.Append(data.ExistsIf.ElementsAs(ctx, &existsIf, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state := result.Lifecycle()
+	data.LifecycleState = stringOrNull(state)
+	result = result.ExistsIn(existsIf)
+
+	data.Compatible, data.Conflicts = types.BoolNull(), types.ListNull(types.StringType)
+	if desired != nil {
+		resp.Diagnostics.Append(d.checkCompatibility(ctx, &data, resourceType, desired, result)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	data.Owned, data.Foreign, data.Untagged = types.BoolNull(), types.BoolNull(), types.BoolNull()
+	if hasRule {
+		class := ""
+		if result.Exists {
+			class = rule.Classify(result.Tags)
+		}
+		data.Owned = types.BoolValue(class == OwnershipOwned)
+		data.Foreign = types.BoolValue(class == OwnershipForeign)
+		data.Untagged = types.BoolValue(class == OwnershipUntagged)
+	}
 
 	if !result.Exists {
 		// Resource not found - NOT AN ERROR
 		data.Exists = types.BoolValue(false)
 		data.Arn = types.StringNull()
 		data.Properties = types.DynamicNull()
+		data.PropertiesJSON = types.StringNull()
+		data.Sensitive = types.DynamicNull()
+		data.Status = stringOrNull(result.Status)
+		data.TerraformType = types.StringNull()
+		data.ImportID = types.StringNull()
 		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
-	// Convert properties to Terraform dynamic type
-	props, propsDiags := convertMapToDynamic(result.Properties)
-	resp.Diagnostics.Append(propsDiags...)
-	if resp.Diagnostics.HasError() {
+	// Keep sensitive properties out of plan output
+	properties, sensitive := splitSensitive(result.Properties, sensitiveKeys(prober))
+
+	data.Sensitive = types.DynamicNull()
+	if sensitive != nil && !d.dropSensitive {
+		sensitiveProps, diags := convertMapToDynamic(sensitive)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.Sensitive = sensitiveProps
+	}
+
+	propsJSON, err := canonicalJSON(properties)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode properties", err.Error())
 		return
 	}
+	data.PropertiesJSON = types.StringValue(propsJSON)
+
+	// Convert properties to Terraform dynamic type
+	data.Properties = types.DynamicNull()
+	if data.PropertiesFormat.ValueString() != PropertiesFormatJSON {
+		props, propsDiags := convertMapToDynamic(properties)
+		resp.Diagnostics.Append(propsDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.Properties = props
+	}
 
 	data.Exists = types.BoolValue(true)
-	data.Properties = props
 	if result.Arn != "" {
 		data.Arn = types.StringValue(result.Arn)
 	} else {
 		data.Arn = types.StringNull()
 	}
+	data.Status = stringOrNull(result.Status)
+	data.TerraformType = stringOrNull(result.TerraformType)
+	data.ImportID = stringOrNull(result.ImportID)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// checkCompatibility compares desired with the immutable properties of the
+// probed resource and sets compatible and conflicts.
+func (d *ProbeDataSource) checkCompatibility(ctx context.Context, data *ProbeDataSourceModel, resourceType string, desired map[string]any, result *probe.ProbeResult) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var immutable []probe.ImmutableProperty
+	if prober, err := d.registry.GetProber(resourceType); err == nil {
+		if ip, ok := prober.(probe.ImmutableProber); ok {
+			immutable = ip.ImmutableProperties()
+		}
+	}
+
+	if unknown := checkDesired(desired, immutable); len(unknown) > 0 {
+		diags.AddAttributeError(
+			path.Root("desired"),
+			"Invalid desired properties",
+			fmt.Sprintf("%s are not immutable properties of %s. Immutable properties: %s.",
+				strings.Join(unknown, ", "), resourceType, immutableNames(immutable)),
+		)
+		return diags
+	}
+
+	conflicts := []string{}
+	if result.Exists {
+		conflicts = conflictingProperties(desired, result.Properties, immutable)
+	}
+
+	data.Compatible = types.BoolValue(len(conflicts) == 0)
+	data.Conflicts, diags = types.ListValueFrom(ctx, types.StringType, conflicts)
+	return diags
+}
+
+// runProbe probes a resource of resourceType with the prober registry
+// supplies, reporting unsupported types, services the emulator lacks and
+// probe failures as diagnostics.
+func runProbe(ctx context.Context, registry *probe.ProberRegistry, emulator *EmulatorInfo, resourceType, identifier string) (*probe.ProbeResult, diag.Diagnostics) {
+	prober, diags := resolveProber(registry, emulator, resourceType, identifier)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	// Probe the resource
+	result, err := prober.Probe(ctx, identifier)
+	if err != nil {
+		diags.AddError("Probe failed", err.Error())
+		return nil, diags
+	}
+
+	return result, diags
+}
+
+// resolveProber returns the prober for a resource of resourceType, reporting
+// unsupported types, malformed identifiers and services the emulator lacks
+// as diagnostics.
+func resolveProber(registry *probe.ProberRegistry, emulator *EmulatorInfo, resourceType, identifier string) (probe.ResourceProber, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// Get the appropriate prober for this resource type
+	prober, err := registry.GetProber(resourceType)
+	if err != nil {
+		diags.Append(unsupportedType(registry, resourceType))
+		return nil, diags
+	}
+
+	// Check the identifier in case it was unknown during validation
+	diags.Append(checkIdentifier(prober, resourceType, identifier)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	// A service the emulator lacks fails every call; say so instead
+	if sp, ok := prober.(probe.ServiceProber); ok && sp.Service() != "" {
+		diags.Append(emulator.CheckService(sp.Service())...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	return prober, diags
+}
+
+// validateTarget checks the type and id arguments of a probe before it runs.
+// Until the provider is configured, types are checked against the catalog it
+// was built with, and since prober_definitions haven't been loaded yet, a
+// type missing from it is only a warning when the provider may define it.
+func validateTarget(registry *probe.ProberRegistry, hints *providerHints, resourceType, identifier types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if resourceType.IsNull() || resourceType.IsUnknown() {
+		return diags
+	}
+
+	configured := registry != nil
+	if !configured {
+		registry = hints.registry()
+	}
+
+	prober, err := registry.GetProber(resourceType.ValueString())
+	if err != nil {
+		unsupported := unsupportedType(registry, resourceType.ValueString())
+		if !configured && hints.mayDefineTypes() {
+			unsupported = diag.NewAttributeWarningDiagnostic(
+				path.Root("type"),
+				"Possibly Unsupported Resource Type",
+				unsupported.Detail()+". It may be declared in prober_definitions, which are loaded when the provider is configured.",
+			)
+		}
+		diags.Append(unsupported)
+		return diags
+	}
+
+	if identifier.IsNull() || identifier.IsUnknown() {
+		return diags
+	}
+	diags.Append(checkIdentifier(prober, resourceType.ValueString(), identifier.ValueString())...)
+	return diags
+}
+
+// unsupportedType reports that registry has no prober for resourceType,
+// suggesting the closest name it does have.
+func unsupportedType(registry *probe.ProberRegistry, resourceType string) diag.Diagnostic {
+	detail := fmt.Sprintf("Resource type %q is not supported.", resourceType)
+	if suggestion := registry.Catalog().Suggest(resourceType); suggestion != "" {
+		detail += fmt.Sprintf(" Did you mean %q?", suggestion)
+	}
+	detail += fmt.Sprintf(" Supported types: %s", strings.Join(registry.SupportedTypes(), ", "))
+
+	return diag.NewAttributeErrorDiagnostic(path.Root("type"), "Unsupported Resource Type", detail)
+}
+
+// checkIdentifier reports an identifier prober knows can't name a resource,
+// and warns about one that breaks naming rules older resources may predate.
+func checkIdentifier(prober probe.ResourceProber, resourceType, identifier string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if iv, ok := prober.(probe.IdentifierValidator); ok {
+		if err := iv.ValidateIdentifier(identifier); err != nil {
+			diags.AddAttributeError(
+				path.Root("id"),
+				"Invalid Resource Identifier",
+				fmt.Sprintf("%q is not a valid %s identifier: %s.", identifier, resourceType, err),
+			)
+			return diags
+		}
+	}
+	if il, ok := prober.(probe.IdentifierLinter); ok {
+		if err := il.LintIdentifier(identifier); err != nil {
+			diags.AddAttributeWarning(
+				path.Root("id"),
+				"Unusual Resource Identifier",
+				fmt.Sprintf("%q breaks the current %s naming rules: %s. It is probed anyway, since older resources may predate the rules.", identifier, resourceType, err),
+			)
+		}
+	}
+	return diags
+}
+
+// stringOrNull returns a null string for empty values.
+func stringOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// canonicalJSON encodes props as JSON with object keys sorted at every level
+// and no insignificant whitespace, so equal properties always encode the same
+// way. SDK structs are encoded by their JSON form, numbers keep their
+// precision, and nil or empty properties encode as {}.
+func canonicalJSON(props map[string]any) (string, error) {
+	if props == nil {
+		props = map[string]any{}
+	}
+
+	encoded, err := marshalJSON(props)
+	if err != nil {
+		return "", err
+	}
+
+	// Decode into generic maps, which encoding/json writes with sorted keys
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return "", err
+	}
+
+	encoded, err = marshalJSON(generic)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// marshalJSON encodes v like json.Marshal without escaping HTML characters.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
 // convertMapToDynamic converts a map[string]any to a Terraform dynamic value.
 func convertMapToDynamic(props map[string]any) (types.Dynamic, diag.Diagnostics) {
 	var diags diag.Diagnostics
@@ -192,9 +693,18 @@ func convertToAttrValue(v any) (attr.Value, error) {
 			}
 			elements[i] = converted
 		}
-		// Determine element type from first element
-		elemType := elements[0].Type(context.Background())
-		return types.ListValueMust(elemType, elements), nil
+		// A list needs one element type; elements of different types, such
+		// as objects with different keys, make a tuple instead
+		elemTypes := make([]attr.Type, len(elements))
+		uniform := true
+		for i, elem := range elements {
+			elemTypes[i] = elem.Type(context.Background())
+			uniform = uniform && elemTypes[i].Equal(elemTypes[0])
+		}
+		if uniform {
+			return types.ListValueMust(elemTypes[0], elements), nil
+		}
+		return types.TupleValueMust(elemTypes, elements), nil
 	case map[string]any:
 		if len(val) == 0 {
 			return types.MapNull(types.StringType), nil
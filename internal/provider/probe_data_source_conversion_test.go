@@ -390,6 +390,37 @@ func TestConvertToAttrValue_SliceOfMaps(t *testing.T) {
 	}
 }
 
+func TestConvertToAttrValue_HeterogeneousSlice(t *testing.T) {
+	// Elements of different types, as declarative responses and overrides
+	// can produce, don't fit a list
+	input := []any{
+		map[string]any{"Key": "Team", "Value": "data"},
+		map[string]any{"Key": "Retired"},
+		"loose",
+	}
+
+	result, err := convertToAttrValue(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tupleVal, ok := result.(types.Tuple)
+	if !ok {
+		t.Fatalf("expected types.Tuple, got %T", result)
+	}
+	if len(tupleVal.Elements()) != 3 {
+		t.Fatalf("expected 3 elements, got %d", len(tupleVal.Elements()))
+	}
+
+	dynamic, diags := convertMapToDynamic(map[string]any{"Tags": input})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if _, err := dynamic.ToTerraformValue(context.Background()); err != nil {
+		t.Errorf("expected a valid Terraform value, got %v", err)
+	}
+}
+
 func TestConvertToAttrValue_IntSlice(t *testing.T) {
 	// Test slice of numeric values (all same type)
 	input := []any{float64(1), float64(2), float64(3)}
@@ -415,3 +446,50 @@ func TestConvertToAttrValue_IntSlice(t *testing.T) {
 		t.Errorf("expected element type to be Float64Type, got %v", elemType)
 	}
 }
+
+func TestCanonicalJSON(t *testing.T) {
+	type keyElement struct {
+		AttributeName string
+		KeyType       string
+	}
+
+	tests := []struct {
+		name     string
+		props    map[string]any
+		expected string
+	}{
+		{name: "nil", props: nil, expected: `{}`},
+		{
+			name: "sorted keys",
+			props: map[string]any{
+				"TableStatus": "ACTIVE",
+				"Tags":        map[string]string{"team": "data", "env": "prod"},
+				"ItemCount":   int64(9007199254740993),
+			},
+			expected: `{"ItemCount":9007199254740993,"TableStatus":"ACTIVE","Tags":{"env":"prod","team":"data"}}`,
+		},
+		{
+			name: "sdk structs",
+			props: map[string]any{
+				"KeySchema": []keyElement{{AttributeName: "pk", KeyType: "HASH"}},
+			},
+			expected: `{"KeySchema":[{"AttributeName":"pk","KeyType":"HASH"}]}`,
+		},
+		{
+			name:     "no html escaping",
+			props:    map[string]any{"Policy": "a<b&c>d"},
+			expected: `{"Policy":"a<b&c>d"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := canonicalJSON(tt.props)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("canonicalJSON() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
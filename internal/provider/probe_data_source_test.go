@@ -4,14 +4,28 @@
 package provider
 
 import (
+	"context"
 	"net/http"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
-	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/internal/faults"
+	"github.com/shakefu/terraform-provider-probe/probe"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -35,15 +49,10 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
-// localStackRunning checks if LocalStack is available at localhost:4566
+// localStackRunning checks if LocalStack is available where the provider
+// would detect it.
 func localStackRunning() bool {
-	client := &http.Client{Timeout: 500 * time.Millisecond}
-	resp, err := client.Get("http://localhost:4566/_localstack/health")
-	if err != nil {
-		return false
-	}
-	defer resp.Body.Close()
-	return resp.StatusCode == http.StatusOK
+	return detectLocalStack(context.Background()) != nil
 }
 
 // useLocalStack returns true if tests should use LocalStack
@@ -51,6 +60,465 @@ func useLocalStack() bool {
 	return localStackRunning()
 }
 
+// testAccFakeAWS starts a fake AWS server for hermetic acceptance tests.
+// Point the provider at it with server.ProviderConfig().
+func testAccFakeAWS(t *testing.T) *fakeaws.Server {
+	t.Helper()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	return fakeaws.New(t)
+}
+
+// objectValue builds a value of objType from values; unset attributes are
+// null.
+func objectValue(objType tftypes.Object, values map[string]tftypes.Value) tftypes.Value {
+	attrs := make(map[string]tftypes.Value, len(objType.AttributeTypes))
+	for name, typ := range objType.AttributeTypes {
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+		} else {
+			attrs[name] = tftypes.NewValue(typ, nil)
+		}
+	}
+	return tftypes.NewValue(objType, attrs)
+}
+
+// readProbe runs d.Read with the given configuration values; unset
+// attributes are null.
+func readProbe(t *testing.T, d *ProbeDataSource, values map[string]tftypes.Value) (ProbeDataSourceModel, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, values)},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, nil)},
+	}
+	d.Read(ctx, req, &resp)
+
+	var model ProbeDataSourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	}
+	return model, resp.Diagnostics
+}
+
+func TestProbeDataSource_ValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	cfg := aws.Config{Region: "us-east-1"}
+
+	validate := func(d *ProbeDataSource, resourceType, id, format, existsIf, onError tftypes.Value) diag.Diagnostics {
+		var schemaResp datasource.SchemaResponse
+		d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+		objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+		var resp datasource.ValidateConfigResponse
+		d.ValidateConfig(ctx, datasource.ValidateConfigRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, map[string]tftypes.Value{
+				"type":              resourceType,
+				"id":                id,
+				"properties_format": format,
+				"exists_if":         existsIf,
+				"on_error":          onError,
+			})},
+		}, &resp)
+		return resp.Diagnostics
+	}
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+	unknown := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+
+	configured := &ProbeDataSource{registry: probe.NewProberRegistry(cfg)}
+	unconfigured := &ProbeDataSource{}
+
+	tests := []struct {
+		name        string
+		d           *ProbeDataSource
+		typ, id     tftypes.Value
+		format      string
+		existsIf    []string
+		onError     string
+		wantPath    path.Path
+		wantDetail  string
+		wantWarning string
+	}{
+		{name: "valid", d: configured, typ: str("aws_s3_bucket"), id: str("assets")},
+		{
+			name: "type typo", d: configured, typ: str("aws_dynamo_table"), id: str("orders"),
+			wantPath: path.Root("type"), wantDetail: `Did you mean "aws_dynamodb_table"?`,
+		},
+		{
+			name: "cloudformation typo", d: configured, typ: str("AWS::S3::Buckets"), id: str("assets"),
+			wantPath: path.Root("type"), wantDetail: `Did you mean "AWS::S3::Bucket"?`,
+		},
+		{
+			name: "invalid identifier", d: configured, typ: str("aws_s3_bucket"), id: str("assets/logs"),
+			wantPath: path.Root("id"), wantDetail: "letters, digits, periods",
+		},
+		{
+			name: "invalid identifier before configure", d: unconfigured, typ: str("AWS::DynamoDB::Table"), id: str("x"),
+			wantPath: path.Root("id"), wantDetail: "3 to 255 characters",
+		},
+		{
+			name: "unknown type before configure", d: unconfigured, typ: str("aws_iam_role"), id: str("deploy"),
+			wantWarning: "prober_definitions",
+		},
+		{
+			name: "legacy identifier", d: configured, typ: str("aws_s3_bucket"), id: str("Legacy_Assets"),
+			wantWarning: "lowercase letters",
+		},
+		{name: "unknown id", d: configured, typ: str("aws_s3_bucket"), id: unknown},
+		{name: "unknown type", d: configured, typ: unknown, id: str("Assets")},
+		{
+			name: "invalid lifecycle state", d: configured, typ: str("aws_s3_bucket"), id: str("assets"), existsIf: []string{"active", "deleted"},
+			wantPath: path.Root("exists_if").AtListIndex(1), wantDetail: `"deleted" is not a lifecycle state`,
+		},
+		{
+			name: "empty lifecycle states", d: configured, typ: str("aws_s3_bucket"), id: str("assets"), existsIf: []string{},
+			wantPath: path.Root("exists_if"), wantDetail: "at least one lifecycle state",
+		},
+		{name: "on_error policy", d: configured, typ: str("aws_s3_bucket"), id: str("assets"), onError: OnErrorAssumeMissing},
+		{
+			name: "invalid on_error policy", d: configured, typ: str("aws_s3_bucket"), id: str("assets"), onError: "ignore",
+			wantPath: path.Root("on_error"), wantDetail: `"ignore" is not an on_error policy`,
+		},
+		{name: "json properties format", d: configured, typ: str("aws_s3_bucket"), id: str("assets"), format: PropertiesFormatJSON},
+		{
+			name: "invalid properties format", d: configured, typ: str("aws_s3_bucket"), id: str("assets"), format: "yaml",
+			wantPath: path.Root("properties_format"), wantDetail: `got "yaml"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format := tftypes.NewValue(tftypes.String, nil)
+			if tt.format != "" {
+				format = str(tt.format)
+			}
+			existsIf := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil)
+			if tt.existsIf != nil {
+				states := make([]tftypes.Value, len(tt.existsIf))
+				for i, state := range tt.existsIf {
+					states[i] = str(state)
+				}
+				existsIf = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, states)
+			}
+			onError := tftypes.NewValue(tftypes.String, nil)
+			if tt.onError != "" {
+				onError = str(tt.onError)
+			}
+			diags := validate(tt.d, tt.typ, tt.id, format, existsIf, onError)
+			if tt.wantDetail == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", diags)
+				}
+				warnings := diags.Warnings()
+				if tt.wantWarning == "" && len(warnings) > 0 {
+					t.Errorf("unexpected warnings: %v", warnings)
+				}
+				if tt.wantWarning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), tt.wantWarning)) {
+					t.Errorf("expected a warning containing %q, got %v", tt.wantWarning, warnings)
+				}
+				return
+			}
+			if !diags.HasError() {
+				t.Fatal("expected an error")
+			}
+			withPath, ok := diags[0].(diag.DiagnosticWithPath)
+			if !ok || !withPath.Path().Equal(tt.wantPath) {
+				t.Errorf("expected the error on %s, got %v", tt.wantPath, diags[0])
+			}
+			if !strings.Contains(diags[0].Detail(), tt.wantDetail) {
+				t.Errorf("expected %q in the detail, got %q", tt.wantDetail, diags[0].Detail())
+			}
+		})
+	}
+}
+
+// Terraform validates probes before it configures the provider, once the
+// provider's own configuration has been validated.
+func TestProbeProvider_ValidateBeforeConfigure(t *testing.T) {
+	ctx := context.Background()
+
+	// newProvider returns a provider whose configuration has been validated,
+	// with prober_definitions set to definitions.
+	newProvider := func(t *testing.T, definitions tftypes.Value) *ProbeProvider {
+		t.Helper()
+		p := New("test")().(*ProbeProvider)
+		var schemaResp provider.SchemaResponse
+		p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+		objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+		var resp provider.ValidateConfigResponse
+		p.ValidateConfig(ctx, provider.ValidateConfigRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, map[string]tftypes.Value{
+				"prober_definitions": definitions,
+			})},
+		}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		return p
+	}
+	values := map[string]tftypes.Value{
+		"type": tftypes.NewValue(tftypes.String, "aws_dynamo_table"),
+		"id":   tftypes.NewValue(tftypes.String, "orders"),
+	}
+
+	// validate runs ValidateConfig on every probe the provider offers.
+	validate := func(p *ProbeProvider) map[string]diag.Diagnostics {
+		results := map[string]diag.Diagnostics{}
+
+		d := p.DataSources(ctx)[0]()
+		var dsSchema datasource.SchemaResponse
+		d.Schema(ctx, datasource.SchemaRequest{}, &dsSchema)
+		dsType := dsSchema.Schema.Type().TerraformType(ctx).(tftypes.Object)
+		var dsResp datasource.ValidateConfigResponse
+		d.(datasource.DataSourceWithValidateConfig).ValidateConfig(ctx, datasource.ValidateConfigRequest{
+			Config: tfsdk.Config{Schema: dsSchema.Schema, Raw: objectValue(dsType, values)},
+		}, &dsResp)
+		results["probe"] = dsResp.Diagnostics
+
+		for i, name := range []string{"probe_adoption", "probe_wait"} {
+			r := p.Resources(ctx)[i]()
+			var rSchema fwresource.SchemaResponse
+			r.Schema(ctx, fwresource.SchemaRequest{}, &rSchema)
+			rType := rSchema.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			var rResp fwresource.ValidateConfigResponse
+			r.(fwresource.ResourceWithValidateConfig).ValidateConfig(ctx, fwresource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: rSchema.Schema, Raw: objectValue(rType, values)},
+			}, &rResp)
+			results[name] = rResp.Diagnostics
+		}
+		return results
+	}
+
+	t.Run("without prober_definitions", func(t *testing.T) {
+		for name, diags := range validate(newProvider(t, tftypes.NewValue(tftypes.String, nil))) {
+			if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), `Did you mean "aws_dynamodb_table"?`) {
+				t.Errorf("%s: expected a suggestion, got %v", name, diags)
+			}
+		}
+	})
+
+	t.Run("with prober_definitions", func(t *testing.T) {
+		for _, definitions := range []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "probers.yaml"),
+			tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		} {
+			for name, diags := range validate(newProvider(t, definitions)) {
+				if diags.HasError() {
+					t.Fatalf("%s: unexpected diagnostics: %v", name, diags)
+				}
+				warnings := diags.Warnings()
+				if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), `Did you mean "aws_dynamodb_table"?`) {
+					t.Errorf("%s: expected a warning with a suggestion, got %v", name, diags)
+				}
+			}
+		}
+	})
+}
+
+func TestProbeDataSource_PropertiesFormat(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutBucket("assets", fakeaws.Bucket{Region: "eu-west-1"})
+	d := &ProbeDataSource{registry: probe.NewProberRegistry(cfg)}
+
+	read := func(format, id string) ProbeDataSourceModel {
+		t.Helper()
+		values := map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "aws_s3_bucket"),
+			"id":   tftypes.NewValue(tftypes.String, id),
+		}
+		if format != "" {
+			values["properties_format"] = tftypes.NewValue(tftypes.String, format)
+		}
+		data, diags := readProbe(t, d, values)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		return data
+	}
+
+	data := read("", "assets")
+	if data.Properties.IsNull() {
+		t.Error("expected dynamic properties by default")
+	}
+	if got := data.PropertiesJSON.ValueString(); got != `{"Arn":"arn:aws:s3:::assets","BucketName":"assets","Region":"eu-west-1"}` {
+		t.Errorf("properties_json = %s", got)
+	}
+
+	data = read(PropertiesFormatJSON, "assets")
+	if !data.Properties.IsNull() {
+		t.Errorf("expected null properties with the json format, got %v", data.Properties)
+	}
+	if got := data.PropertiesJSON.ValueString(); got != `{"Arn":"arn:aws:s3:::assets","BucketName":"assets","Region":"eu-west-1"}` {
+		t.Errorf("properties_json = %s", got)
+	}
+
+	if data := read(PropertiesFormatJSON, "missing"); !data.PropertiesJSON.IsNull() {
+		t.Errorf("expected null properties_json for a missing bucket, got %v", data.PropertiesJSON)
+	}
+}
+
+func TestProbeDataSource_ExistsIf(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutTable(fakeaws.Table{Name: "orders"})
+	server.PutTable(fakeaws.Table{Name: "legacy", Status: "DELETING"})
+	d := &ProbeDataSource{registry: probe.NewProberRegistry(cfg)}
+
+	activeOnly := []string{probe.LifecycleActive, probe.LifecycleUpdating}
+	tests := []struct {
+		name       string
+		id         string
+		existsIf   []string
+		wantExists bool
+		wantState  string
+	}{
+		{name: "active", id: "orders", existsIf: activeOnly, wantExists: true, wantState: probe.LifecycleActive},
+		{name: "deleting excluded", id: "legacy", existsIf: activeOnly, wantExists: false, wantState: probe.LifecycleDeleting},
+		{name: "deleting by default", id: "legacy", wantExists: true, wantState: probe.LifecycleDeleting},
+		{name: "missing", id: "ghost", existsIf: activeOnly, wantExists: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]tftypes.Value{
+				"type": tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+				"id":   tftypes.NewValue(tftypes.String, tt.id),
+			}
+			if tt.existsIf != nil {
+				states := make([]tftypes.Value, len(tt.existsIf))
+				for i, state := range tt.existsIf {
+					states[i] = tftypes.NewValue(tftypes.String, state)
+				}
+				values["exists_if"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, states)
+			}
+			data, diags := readProbe(t, d, values)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if data.Exists.ValueBool() != tt.wantExists {
+				t.Errorf("exists = %v, want %v", data.Exists, tt.wantExists)
+			}
+			if data.LifecycleState.ValueString() != tt.wantState {
+				t.Errorf("lifecycle_state = %v, want %q", data.LifecycleState, tt.wantState)
+			}
+			if !tt.wantExists && !data.Arn.IsNull() {
+				t.Errorf("expected a null arn, got %v", data.Arn)
+			}
+		})
+	}
+}
+
+func TestAccProbeDataSource_fake(t *testing.T) {
+	server := testAccFakeAWS(t)
+	server.PutTable(fakeaws.Table{
+		Name: "contacts",
+		Tags: map[string]string{"Environment": "test"},
+	})
+	server.PutBucket("assets", fakeaws.Bucket{Region: "eu-west-1"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccProbeDataSourceConfig_fake,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.probe.table", "exists", "true"),
+					resource.TestCheckResourceAttr("data.probe.table", "arn", "arn:aws:dynamodb:us-east-1:123456789012:table/contacts"),
+					resource.TestCheckResourceAttr("data.probe.table", "properties.TableStatus", "ACTIVE"),
+					resource.TestCheckResourceAttr("data.probe.table", "properties.Tags.Environment", "test"),
+					resource.TestCheckResourceAttr("data.probe.table", "import_id", "contacts"),
+					resource.TestCheckResourceAttr("data.probe.bucket", "exists", "true"),
+					resource.TestCheckResourceAttr("data.probe.bucket", "properties.Region", "eu-west-1"),
+					resource.TestCheckResourceAttr("data.probe.missing", "exists", "false"),
+					resource.TestCheckNoResourceAttr("data.probe.missing", "arn"),
+				),
+			},
+		},
+	})
+}
+
+const testAccProbeDataSourceConfig_fake = `
+data "probe" "table" {
+  type = "AWS::DynamoDB::Table"
+  id   = "contacts"
+}
+
+data "probe" "bucket" {
+  type = "aws_s3_bucket"
+  id   = "assets"
+}
+
+data "probe" "missing" {
+  type = "aws_s3_bucket"
+  id   = "does-not-exist"
+}
+`
+
+func TestAccProbeDataSource_fakeError(t *testing.T) {
+	server := testAccFakeAWS(t)
+	server.Fail(fakeaws.OpDescribeTable, fakeaws.Error{Status: http.StatusBadRequest, Code: "AccessDeniedException", Message: "not authorized"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      server.ProviderConfig() + testAccProbeDataSourceConfig_fake,
+				ExpectError: regexp.MustCompile(`AccessDeniedException`),
+			},
+		},
+	})
+}
+
+// testAccFaultProviderFactories returns provider factories whose AWS calls
+// go through transport.
+func testAccFaultProviderFactories(transport *faults.Transport) map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"probe": func() (tfprotov6.ProviderServer, error) {
+			p := NewWithCatalog("test", probe.DefaultCatalog())().(*ProbeProvider)
+			p.httpClient = transport.Client()
+			return providerserver.NewProtocol6WithError(p)()
+		},
+	}
+}
+
+func TestAccProbeDataSource_faults(t *testing.T) {
+	server := testAccFakeAWS(t)
+	server.PutTable(fakeaws.Table{Name: "contacts"})
+	server.PutBucket("assets", fakeaws.Bucket{})
+
+	transport := faults.NewTransport(nil)
+	transport.InjectN("dynamodb", fakeaws.OpDescribeTable, 1, faults.Unavailable())
+	transport.InjectN("s3", fakeaws.OpHeadBucket, 1, faults.ConnectionReset())
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccFaultProviderFactories(transport),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccProbeDataSourceConfig_fake,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.probe.table", "exists", "true"),
+					resource.TestCheckResourceAttr("data.probe.bucket", "exists", "true"),
+				),
+			},
+			{
+				PreConfig: func() {
+					transport.Inject("dynamodb", fakeaws.OpDescribeTable, faults.ExpiredCredentials())
+				},
+				Config:      server.ProviderConfig() + testAccProbeDataSourceConfig_fake,
+				ExpectError: regexp.MustCompile(`ExpiredTokenException`),
+			},
+		},
+	})
+}
+
 func TestAccProbeDataSource_notFound(t *testing.T) {
 	config := testAccProbeDataSourceConfig_notFound
 	if useLocalStack() {
@@ -67,6 +535,7 @@ func TestAccProbeDataSource_notFound(t *testing.T) {
 					resource.TestCheckResourceAttr("data.probe.test", "exists", "false"),
 					resource.TestCheckNoResourceAttr("data.probe.test", "arn"),
 					resource.TestCheckNoResourceAttr("data.probe.test", "properties"),
+					resource.TestCheckNoResourceAttr("data.probe.test", "import_id"),
 				),
 			},
 		},
@@ -107,6 +576,8 @@ func TestAccProbeDataSource_existingResource(t *testing.T) {
 			resource.TestCheckResourceAttrSet("data.probe.test", "arn"),
 			resource.TestCheckResourceAttrSet("data.probe.test", "properties.%"),
 			resource.TestCheckResourceAttr("data.probe.test", "properties.TableName", "probe-acceptance-test-table"),
+			resource.TestCheckResourceAttr("data.probe.test", "terraform_type", "aws_dynamodb_table"),
+			resource.TestCheckResourceAttr("data.probe.test", "import_id", "probe-acceptance-test-table"),
 		)
 	}
 
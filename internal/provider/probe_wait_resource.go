@@ -0,0 +1,485 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/dynamicplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// Ensure ProbeWaitResource satisfies various resource interfaces.
+var _ resource.Resource = &ProbeWaitResource{}
+var _ resource.ResourceWithConfigure = &ProbeWaitResource{}
+var _ resource.ResourceWithValidateConfig = &ProbeWaitResource{}
+
+// Defaults for the probe_wait timing arguments.
+const (
+	defaultWaitTimeout      = "10m"
+	defaultWaitPollInterval = "10s"
+)
+
+// ProbeWaitResource blocks apply until a resource exists and matches the
+// configured conditions. Read probes again, so deleting the target shows up
+// as drift and the next apply waits for it again.
+type ProbeWaitResource struct {
+	registry *probe.ProberRegistry
+	emulator *EmulatorInfo
+	clients  *ClientPool
+
+	// dropSensitive leaves sensitive properties out of state.
+	dropSensitive bool
+
+	// hints describes the provider before it's configured.
+	hints *providerHints
+}
+
+// ProbeWaitResourceModel describes the resource data model.
+type ProbeWaitResourceModel struct {
+	Type            types.String  `tfsdk:"type"`
+	ID              types.String  `tfsdk:"id"`
+	Status          types.String  `tfsdk:"status"`
+	PropertiesMatch types.Map     `tfsdk:"properties_match"`
+	Timeout         types.String  `tfsdk:"timeout"`
+	PollInterval    types.String  `tfsdk:"poll_interval"`
+	Arn             types.String  `tfsdk:"arn"`
+	Properties      types.Dynamic `tfsdk:"properties"`
+	Sensitive       types.Dynamic `tfsdk:"sensitive_properties"`
+	ObservedStatus  types.String  `tfsdk:"observed_status"`
+}
+
+func NewProbeWaitResource() resource.Resource {
+	return &ProbeWaitResource{}
+}
+
+func (r *ProbeWaitResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wait"
+}
+
+func (r *ProbeWaitResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Waits during apply until an AWS resource exists and matches optional conditions. " +
+			"If the resource is deleted later, the wait is planned again.",
+
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Description: "Resource type (e.g., aws_s3_bucket or AWS::S3::Bucket).",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Description: "Resource identifier (table name, bucket name, etc.).",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "Also wait until the service reports this status (e.g., ACTIVE). Compared case-insensitively.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"properties_match": schema.MapAttribute{
+				Description: "Also wait until each property, by dotted path (e.g., BillingModeSummary.BillingMode), has the given value.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				Description: "How long to wait before failing, as a Go duration (e.g., 30m). Defaults to " + defaultWaitTimeout + ".",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultWaitTimeout),
+			},
+			"poll_interval": schema.StringAttribute{
+				Description: "How long to wait between probes, as a Go duration. Defaults to " + defaultWaitPollInterval + ".",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultWaitPollInterval),
+			},
+			"arn": schema.StringAttribute{
+				Description: "ARN of the resource when the wait ended.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"properties": schema.DynamicAttribute{
+				Description: "Resource properties when the wait ended, refreshed on read.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Dynamic{
+					dynamicplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sensitive_properties": schema.DynamicAttribute{
+				Description: "Properties the prober classifies as sensitive, left out of properties and refreshed on read (null if there are none or the provider sets drop_sensitive_properties).",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.Dynamic{
+					dynamicplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"observed_status": schema.StringAttribute{
+				Description: "Resource status as the service reports it, refreshed on read.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ProbeWaitResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProbeProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProbeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	// Without a known provider configuration, plans leave probe results
+	// unknown until apply
+	if len(providerData.UnknownConfig) > 0 {
+		return
+	}
+
+	r.registry = providerData.Registry
+	r.emulator = providerData.Emulator
+	r.clients = providerData.Clients
+	r.dropSensitive = providerData.DropSensitive
+}
+
+func (r *ProbeWaitResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ProbeWaitResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags := waitDurations(data)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(validateTarget(r.registry, r.hints, data.Type, data.ID)...)
+}
+
+func (r *ProbeWaitResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ProbeWaitResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timing, diags := waitDurations(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conditions := make(map[string]string)
+	resp.Diagnostics.Append(data.PropertiesMatch.ElementsAs(ctx, &conditions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	result, diags := r.wait(ctx, data, conditions, timing)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.setObserved(&data, result)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read probes the resource again. A resource that no longer exists is
+// removed from state, so the next plan waits for it again.
+func (r *ProbeWaitResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ProbeWaitResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keep the last observation until the provider configuration is known
+	if r.registry == nil {
+		return
+	}
+
+	result, diags := runProbe(withClientPool(ctx, r.clients), r.registry, r.emulator, data.Type.ValueString(), data.ID.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !result.Exists {
+		tflog.Info(ctx, "Waited-for resource no longer exists", map[string]any{
+			"type": data.Type.ValueString(),
+			"id":   data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(r.setObserved(&data, result)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update applies new timing arguments; everything else forces replacement.
+func (r *ProbeWaitResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data ProbeWaitResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete forgets the wait. The target resource is left alone.
+func (r *ProbeWaitResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// waitTiming holds the parsed timeout and poll_interval.
+type waitTiming struct {
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// waitDurations parses timeout and poll_interval. Unknown or null values
+// get their defaults.
+func waitDurations(data ProbeWaitResourceModel) (waitTiming, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var timing waitTiming
+
+	for _, d := range []struct {
+		name     string
+		value    types.String
+		fallback string
+		target   *time.Duration
+	}{
+		{"timeout", data.Timeout, defaultWaitTimeout, &timing.timeout},
+		{"poll_interval", data.PollInterval, defaultWaitPollInterval, &timing.pollInterval},
+	} {
+		value := d.fallback
+		if !d.value.IsNull() && !d.value.IsUnknown() {
+			value = d.value.ValueString()
+		}
+
+		parsed, err := time.ParseDuration(value)
+		if err == nil && parsed <= 0 {
+			err = fmt.Errorf("must be positive")
+		}
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root(d.name),
+				"Invalid Duration",
+				fmt.Sprintf("%s %q is not a valid duration: %v", d.name, value, err),
+			)
+			continue
+		}
+		*d.target = parsed
+	}
+
+	return timing, diags
+}
+
+// wait probes until the resource exists and matches or the timeout
+// elapses. Failed probes are retried until then, since AWS errors during a
+// wait are often transient.
+func (r *ProbeWaitResource) wait(ctx context.Context, data ProbeWaitResourceModel, conditions map[string]string, timing waitTiming) (*probe.ProbeResult, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if r.registry == nil {
+		diags.AddError(
+			"Provider Not Configured",
+			"The probe provider must be configured before a wait can run.",
+		)
+		return nil, diags
+	}
+
+	resourceType, identifier := data.Type.ValueString(), data.ID.ValueString()
+	prober, proberDiags := resolveProber(r.registry, r.emulator, resourceType, identifier)
+	diags.Append(proberDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	deadline := time.Now().Add(timing.timeout)
+
+	for attempt := 1; ; attempt++ {
+		var unmet []string
+		result, err := prober.Probe(withClientPool(ctx, r.clients), identifier)
+		if err == nil {
+			unmet, err = unmetConditions(result, data.Status.ValueString(), conditions)
+		}
+		switch {
+		case errors.Is(err, errNoOverride):
+			diags.AddError("Probe failed", err.Error())
+			return nil, diags
+		case err != nil:
+			tflog.Warn(ctx, "Probe failed while waiting; retrying", map[string]any{
+				"type":    resourceType,
+				"id":      identifier,
+				"attempt": attempt,
+				"error":   err.Error(),
+			})
+			unmet = []string{fmt.Sprintf("probe failed: %s", err)}
+		case len(unmet) == 0:
+			return result, diags
+		default:
+			tflog.Debug(ctx, "Waiting for resource", map[string]any{
+				"type":    resourceType,
+				"id":      identifier,
+				"attempt": attempt,
+				"unmet":   unmet,
+			})
+		}
+
+		if time.Now().Add(timing.pollInterval).After(deadline) {
+			diags.AddError(
+				"Timed Out Waiting for Resource",
+				fmt.Sprintf("%s %q did not become ready within %s after %d probes: %s.",
+					resourceType, identifier, timing.timeout, attempt, strings.Join(unmet, "; ")),
+			)
+			return nil, diags
+		}
+
+		select {
+		case <-ctx.Done():
+			diags.AddError("Wait Cancelled", ctx.Err().Error())
+			return nil, diags
+		case <-time.After(timing.pollInterval):
+		}
+	}
+}
+
+// unmetConditions describes each wait condition result doesn't meet yet.
+// Properties are compared in their JSON form, so SDK structs and pointers
+// resolve like the values Terraform sees.
+func unmetConditions(result *probe.ProbeResult, status string, conditions map[string]string) ([]string, error) {
+	if !result.Exists {
+		return []string{"resource does not exist"}, nil
+	}
+
+	var unmet []string
+	if status != "" && !strings.EqualFold(result.Status, status) {
+		unmet = append(unmet, fmt.Sprintf("status is %q, want %q", result.Status, status))
+	}
+	if len(conditions) == 0 {
+		return unmet, nil
+	}
+
+	props, err := normalizeJSON(result.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("reading properties: %w", err)
+	}
+
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok := lookupPath(props, key)
+		if !ok || value == nil {
+			unmet = append(unmet, fmt.Sprintf("property %s is not set", key))
+			continue
+		}
+		if got := conditionValue(value); got != conditions[key] {
+			unmet = append(unmet, fmt.Sprintf("property %s is %q, want %q", key, got, conditions[key]))
+		}
+	}
+	return unmet, nil
+}
+
+// conditionValue formats a normalized property for comparison with a
+// properties_match value: strings as they are, anything else as JSON.
+func conditionValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+// setObserved copies what the probe observed into data, keeping sensitive
+// properties out of properties.
+func (r *ProbeWaitResource) setObserved(data *ProbeWaitResourceModel, result *probe.ProbeResult) diag.Diagnostics {
+	var keys []string
+	if prober, err := r.registry.GetProber(data.Type.ValueString()); err == nil {
+		keys = sensitiveKeys(prober)
+	}
+	properties, sensitive := splitSensitive(result.Properties, keys)
+
+	props, diags := convertMapToDynamic(properties)
+	if diags.HasError() {
+		return diags
+	}
+
+	data.Sensitive = types.DynamicNull()
+	if sensitive != nil && !r.dropSensitive {
+		sensitiveProps, sensitiveDiags := convertMapToDynamic(sensitive)
+		diags.Append(sensitiveDiags...)
+		if diags.HasError() {
+			return diags
+		}
+		data.Sensitive = sensitiveProps
+	}
+
+	data.Arn = stringOrNull(result.Arn)
+	data.Properties = props
+	data.ObservedStatus = stringOrNull(result.Status)
+	return diags
+}
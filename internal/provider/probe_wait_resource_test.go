@@ -0,0 +1,332 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// sequenceProber returns its results in order, repeating the last one.
+// Calls with a non-nil entry in errs fail with it instead.
+type sequenceProber struct {
+	results []*probe.ProbeResult
+	errs    []error
+	calls   int
+}
+
+func (p *sequenceProber) Probe(ctx context.Context, identifier string) (*probe.ProbeResult, error) {
+	defer func() { p.calls++ }()
+	if p.calls < len(p.errs) && p.errs[p.calls] != nil {
+		return nil, p.errs[p.calls]
+	}
+	return p.results[min(p.calls, len(p.results)-1)], nil
+}
+
+// newSequenceWaitResource returns a probe_wait resource whose "test_thing"
+// type is answered by prober.
+func newSequenceWaitResource(prober *sequenceProber) *ProbeWaitResource {
+	catalog := probe.NewCatalog()
+	catalog.Register("test_thing", nil, func(aws.Config) probe.ResourceProber { return prober })
+	return &ProbeWaitResource{registry: probe.NewProberRegistryWithCatalog(aws.Config{}, catalog)}
+}
+
+// createWait runs r.Create with the given configuration values, filling in
+// the defaults and unknown computed values a plan would have.
+func createWait(t *testing.T, r *ProbeWaitResource, values map[string]tftypes.Value) (ProbeWaitResourceModel, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	planned := map[string]tftypes.Value{
+		"timeout":              tftypes.NewValue(tftypes.String, defaultWaitTimeout),
+		"poll_interval":        tftypes.NewValue(tftypes.String, "1ms"),
+		"arn":                  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"properties":           tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue),
+		"sensitive_properties": tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue),
+		"observed_status":      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	}
+	for name, v := range values {
+		planned[name] = v
+	}
+
+	resp := resource.CreateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, nil)},
+	}
+	r.Create(ctx, resource.CreateRequest{
+		Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: objectValue(objType, planned)},
+	}, &resp)
+
+	var data ProbeWaitResourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	}
+	return data, resp.Diagnostics
+}
+
+func TestProbeWaitResource_Create(t *testing.T) {
+	thing := func(extra map[string]tftypes.Value) map[string]tftypes.Value {
+		values := map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "test_thing"),
+			"id":   tftypes.NewValue(tftypes.String, "a"),
+		}
+		for name, v := range extra {
+			values[name] = v
+		}
+		return values
+	}
+	creating := &probe.ProbeResult{Exists: true, Status: "CREATING", Properties: map[string]any{
+		"Encryption": map[string]any{"Status": "ENABLING"},
+	}}
+	active := &probe.ProbeResult{Exists: true, Arn: "arn:test:a", Status: "ACTIVE", Properties: map[string]any{
+		"Encryption": map[string]any{"Status": "ENABLED"},
+		"Tags":       map[string]string{"Team": "data"},
+	}}
+	missing := &probe.ProbeResult{Exists: false}
+
+	t.Run("until exists", func(t *testing.T) {
+		prober := &sequenceProber{results: []*probe.ProbeResult{missing, missing, creating}}
+		data, diags := createWait(t, newSequenceWaitResource(prober), thing(nil))
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if prober.calls != 3 {
+			t.Errorf("expected 3 probes, got %d", prober.calls)
+		}
+		if data.ObservedStatus.ValueString() != "CREATING" {
+			t.Errorf("expected the observed status, got %v", data.ObservedStatus)
+		}
+	})
+
+	t.Run("until status", func(t *testing.T) {
+		prober := &sequenceProber{results: []*probe.ProbeResult{missing, creating, active}}
+		data, diags := createWait(t, newSequenceWaitResource(prober), thing(map[string]tftypes.Value{
+			"status": tftypes.NewValue(tftypes.String, "active"),
+		}))
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if prober.calls != 3 || data.Arn.ValueString() != "arn:test:a" {
+			t.Errorf("expected to stop at the active result, got %d probes and %+v", prober.calls, data)
+		}
+	})
+
+	t.Run("until properties match", func(t *testing.T) {
+		prober := &sequenceProber{results: []*probe.ProbeResult{creating, active}}
+		match := tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"Encryption.Status": tftypes.NewValue(tftypes.String, "ENABLED"),
+			"Tags.Team":         tftypes.NewValue(tftypes.String, "data"),
+		})
+		_, diags := createWait(t, newSequenceWaitResource(prober), thing(map[string]tftypes.Value{
+			"properties_match": match,
+		}))
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if prober.calls != 2 {
+			t.Errorf("expected 2 probes, got %d", prober.calls)
+		}
+	})
+
+	t.Run("probe errors are retried", func(t *testing.T) {
+		throttled := errors.New("ThrottlingException: slow down")
+		prober := &sequenceProber{results: []*probe.ProbeResult{active}, errs: []error{throttled, throttled}}
+		_, diags := createWait(t, newSequenceWaitResource(prober), thing(nil))
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if prober.calls != 3 {
+			t.Errorf("expected 3 probes, got %d", prober.calls)
+		}
+	})
+
+	t.Run("last probe error on timeout", func(t *testing.T) {
+		prober := &sequenceProber{}
+		for i := range 100 {
+			prober.errs = append(prober.errs, fmt.Errorf("failure %d", i))
+		}
+		_, diags := createWait(t, newSequenceWaitResource(prober), thing(map[string]tftypes.Value{
+			"timeout":       tftypes.NewValue(tftypes.String, "20ms"),
+			"poll_interval": tftypes.NewValue(tftypes.String, "5ms"),
+		}))
+		if !diags.HasError() || diags[0].Summary() != "Timed Out Waiting for Resource" {
+			t.Fatalf("expected a timeout, got %v", diags)
+		}
+		last := prober.errs[prober.calls-1]
+		if !strings.Contains(diags[0].Detail(), "probe failed: "+last.Error()) {
+			t.Errorf("expected the last error %q in the detail, got %q", last, diags[0].Detail())
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		prober := &sequenceProber{results: []*probe.ProbeResult{creating}}
+		_, diags := createWait(t, newSequenceWaitResource(prober), thing(map[string]tftypes.Value{
+			"status":        tftypes.NewValue(tftypes.String, "ACTIVE"),
+			"timeout":       tftypes.NewValue(tftypes.String, "20ms"),
+			"poll_interval": tftypes.NewValue(tftypes.String, "5ms"),
+		}))
+		if !diags.HasError() || diags[0].Summary() != "Timed Out Waiting for Resource" {
+			t.Fatalf("expected a timeout, got %v", diags)
+		}
+		if !strings.Contains(diags[0].Detail(), `status is "CREATING", want "ACTIVE"`) {
+			t.Errorf("expected the unmet condition in the detail, got %q", diags[0].Detail())
+		}
+	})
+}
+
+func TestProbeWaitResource_DynamoDB(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutTable(fakeaws.Table{Name: "events"})
+	r := &ProbeWaitResource{registry: probe.NewProberRegistry(cfg)}
+
+	match := func(conditions map[string]string) map[string]tftypes.Value {
+		elements := make(map[string]tftypes.Value, len(conditions))
+		for key, value := range conditions {
+			elements[key] = tftypes.NewValue(tftypes.String, value)
+		}
+		return map[string]tftypes.Value{
+			"type":             tftypes.NewValue(tftypes.String, "aws_dynamodb_table"),
+			"id":               tftypes.NewValue(tftypes.String, "events"),
+			"status":           tftypes.NewValue(tftypes.String, "ACTIVE"),
+			"properties_match": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, elements),
+			"timeout":          tftypes.NewValue(tftypes.String, "50ms"),
+			"poll_interval":    tftypes.NewValue(tftypes.String, "10ms"),
+		}
+	}
+
+	t.Run("nested and pointer properties", func(t *testing.T) {
+		_, diags := createWait(t, r, match(map[string]string{
+			"BillingModeSummary.BillingMode": "PAY_PER_REQUEST",
+			"ItemCount":                      "0",
+			"DeletionProtection":             "false",
+		}))
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		_, diags := createWait(t, r, match(map[string]string{"BillingModeSummary.BillingMode": "PROVISIONED"}))
+		if !diags.HasError() {
+			t.Fatal("expected a timeout")
+		}
+		if !strings.Contains(diags[0].Detail(), `property BillingModeSummary.BillingMode is "PAY_PER_REQUEST", want "PROVISIONED"`) {
+			t.Errorf("unexpected detail %q", diags[0].Detail())
+		}
+	})
+}
+
+func TestProbeWaitResource_ReadDrift(t *testing.T) {
+	prober := &sequenceProber{results: []*probe.ProbeResult{{Exists: true, Status: "ACTIVE"}, {Exists: false}}}
+	r := newSequenceWaitResource(prober)
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	state := objectValue(objType, map[string]tftypes.Value{
+		"type":            tftypes.NewValue(tftypes.String, "test_thing"),
+		"id":              tftypes.NewValue(tftypes.String, "a"),
+		"timeout":         tftypes.NewValue(tftypes.String, defaultWaitTimeout),
+		"poll_interval":   tftypes.NewValue(tftypes.String, defaultWaitPollInterval),
+		"observed_status": tftypes.NewValue(tftypes.String, "CREATING"),
+	})
+	read := func() (resource.ReadResponse, ProbeWaitResourceModel) {
+		resp := resource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: state}}
+		r.Read(ctx, resource.ReadRequest{State: tfsdk.State{Schema: schemaResp.Schema, Raw: state}}, &resp)
+		var data ProbeWaitResourceModel
+		if !resp.State.Raw.IsNull() {
+			resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+		}
+		return resp, data
+	}
+
+	resp, data := read()
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if data.ObservedStatus != types.StringValue("ACTIVE") {
+		t.Errorf("expected the refreshed status, got %v", data.ObservedStatus)
+	}
+
+	resp, _ = read()
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("expected a deleted target to remove the wait from state")
+	}
+}
+
+func TestWaitDurations(t *testing.T) {
+	timing, diags := waitDurations(ProbeWaitResourceModel{
+		Timeout:      types.StringValue("90s"),
+		PollInterval: types.StringNull(),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if timing.timeout.Seconds() != 90 || timing.pollInterval.Seconds() != 10 {
+		t.Errorf("unexpected timing %+v", timing)
+	}
+
+	for _, value := range []string{"soon", "-1s", "0s"} {
+		_, diags := waitDurations(ProbeWaitResourceModel{Timeout: types.StringValue(value), PollInterval: types.StringNull()})
+		if !diags.HasError() || diags[0].Summary() != "Invalid Duration" {
+			t.Errorf("expected %q to be rejected, got %v", value, diags)
+		}
+	}
+}
+
+func TestAccProbeWaitResource(t *testing.T) {
+	server := testAccFakeAWS(t)
+	server.PutBucket("shared-assets", fakeaws.Bucket{Region: "us-east-1"})
+
+	tfresource.Test(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []tfresource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccProbeWaitResourceConfig,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("probe_wait.bucket", "arn", "arn:aws:s3:::shared-assets"),
+					tfresource.TestCheckResourceAttr("probe_wait.bucket", "properties.Region", "us-east-1"),
+				),
+			},
+			{
+				// Deleting the bucket is drift, so the wait is planned again
+				PreConfig:          func() { server.DeleteBucket("shared-assets") },
+				Config:             server.ProviderConfig() + testAccProbeWaitResourceConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+const testAccProbeWaitResourceConfig = `
+resource "probe_wait" "bucket" {
+  type          = "aws_s3_bucket"
+  id            = "shared-assets"
+  timeout       = "1m"
+  poll_interval = "1s"
+}
+`
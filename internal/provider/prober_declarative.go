@@ -0,0 +1,538 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+	"github.com/shakefu/terraform-provider-probe/probe/awsprobe"
+)
+
+// DeclarativeProber probes resources described by a ProberDefinition using
+// signed HTTP requests instead of a generated service client.
+type DeclarativeProber struct {
+	def     ProberDefinition
+	pattern *regexp.Regexp
+	cfg     aws.Config
+	client  aws.HTTPClient
+	signer  *v4.Signer
+	retryer aws.Retryer
+	arns    arnBuilder
+}
+
+// NewDeclarativeProber creates a prober for a validated definition.
+func NewDeclarativeProber(cfg aws.Config, def ProberDefinition) *DeclarativeProber {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var retryer aws.Retryer
+	if cfg.Retryer != nil {
+		retryer = cfg.Retryer()
+	} else {
+		retryer = retry.NewStandard()
+	}
+
+	// Validate has already rejected a pattern that doesn't compile
+	pattern, _ := regexp.Compile(def.IdentifierPattern)
+
+	return &DeclarativeProber{
+		def:     def,
+		pattern: pattern,
+		cfg:     cfg,
+		client:  client,
+		signer:  v4.NewSigner(),
+		retryer: retryer,
+		arns:    newARNBuilder(cfg),
+	}
+}
+
+// Service implements probe.ServiceProber.
+func (p *DeclarativeProber) Service() string {
+	return p.def.Service
+}
+
+// ImmutableProperties implements probe.ImmutableProber.
+func (p *DeclarativeProber) ImmutableProperties() []probe.ImmutableProperty {
+	immutable := make([]probe.ImmutableProperty, len(p.def.ImmutableProperties))
+	for i, name := range p.def.ImmutableProperties {
+		immutable[i] = probe.ImmutableProperty{Name: name}
+	}
+	return immutable
+}
+
+// SensitiveProperties implements probe.SensitiveProber.
+func (p *DeclarativeProber) SensitiveProperties() []string {
+	return p.def.SensitiveProperties
+}
+
+// ValidateIdentifier implements probe.IdentifierValidator with the
+// definition's identifier_pattern.
+func (p *DeclarativeProber) ValidateIdentifier(identifier string) error {
+	if p.pattern == nil || p.pattern.MatchString(identifier) {
+		return nil
+	}
+	return fmt.Errorf("identifiers must match %s", p.def.IdentifierPattern)
+}
+
+// Probe calls the definition's operation with the identifier.
+func (p *DeclarativeProber) Probe(ctx context.Context, identifier string) (*probe.ProbeResult, error) {
+	doc, err := p.call(ctx, identifier)
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && slices.Contains(p.def.NotFoundCodes, apiErr.ErrorCode()) {
+			return &probe.ProbeResult{Exists: false}, nil
+		}
+		return nil, err
+	}
+
+	result := &probe.ProbeResult{
+		Exists:        true,
+		Properties:    map[string]any{},
+		TerraformType: p.def.Type,
+		ImportID:      identifier,
+	}
+
+	if props, ok := lookupPath(doc, p.def.PropertiesPath); ok {
+		if m, ok := props.(map[string]any); ok {
+			result.Properties = m
+		}
+	}
+
+	if p.def.ArnPath != "" {
+		if arn, ok := lookupPath(doc, p.def.ArnPath); ok {
+			if s, ok := arn.(string); ok {
+				result.Arn = s
+			}
+		}
+	}
+	if result.Arn == "" && p.def.ArnTemplate != "" {
+		if result.Arn, err = p.arns.Expand(ctx, p.def.ArnTemplate, identifier); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.def.StatusPath != "" {
+		if status, ok := lookupPath(doc, p.def.StatusPath); ok {
+			result.Status = fmt.Sprint(status)
+			result.LifecycleState = p.def.LifecycleStates[result.Status]
+		}
+	}
+
+	if p.def.TagsPath != "" {
+		if tags, ok := lookupPath(doc, p.def.TagsPath); ok {
+			result.Tags = parseTags(tags)
+			if len(result.Tags) > 0 {
+				result.Properties["Tags"] = result.Tags
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// call sends the operation, retrying according to the configured retryer,
+// and returns the decoded response document.
+func (p *DeclarativeProber) call(ctx context.Context, identifier string) (any, error) {
+	maxAttempts := p.retryer.MaxAttempts()
+
+	for attempt := 1; ; attempt++ {
+		doc, err := p.send(ctx, identifier)
+		if err == nil || attempt >= maxAttempts || !p.retryer.IsErrorRetryable(err) {
+			return doc, err
+		}
+
+		delay, delayErr := p.retryer.RetryDelay(attempt, err)
+		if delayErr != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// send makes a single signed request.
+func (p *DeclarativeProber) send(ctx context.Context, identifier string) (any, error) {
+	body, contentType, err := p.encodeRequest(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := p.endpoint()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.def.Protocol == ProtocolJSON {
+		req.Header.Set("X-Amz-Target", p.def.TargetPrefix+"."+p.def.Operation)
+	}
+
+	creds, err := p.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving credentials: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	if err := p.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), p.def.Service, p.signingRegion(), time.Now()); err != nil {
+		return nil, fmt.Errorf("signing request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, p.decodeError(resp, respBody)
+	}
+
+	if p.def.Protocol == ProtocolQuery {
+		return decodeQueryResponse(respBody, p.def.Operation)
+	}
+
+	var doc any
+	if len(respBody) == 0 {
+		return map[string]any{}, nil
+	}
+	if err := json.Unmarshal(respBody, &doc); err != nil {
+		return nil, fmt.Errorf("decoding %s response: %w", p.def.Operation, err)
+	}
+	return doc, nil
+}
+
+// encodeRequest builds the request body for the definition's protocol.
+func (p *DeclarativeProber) encodeRequest(identifier string) ([]byte, string, error) {
+	if p.def.Protocol == ProtocolQuery {
+		values := url.Values{}
+		values.Set("Action", p.def.Operation)
+		values.Set("Version", p.def.APIVersion)
+		for k, v := range p.def.Parameters {
+			values.Set(k, fmt.Sprint(v))
+		}
+		values.Set(p.def.IdentifierParam, identifier)
+		return []byte(values.Encode()), "application/x-www-form-urlencoded; charset=utf-8", nil
+	}
+
+	params := make(map[string]any, len(p.def.Parameters)+1)
+	for k, v := range p.def.Parameters {
+		params[k] = v
+	}
+	params[p.def.IdentifierParam] = identifier
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, "", err
+	}
+
+	version := p.def.JSONVersion
+	if version == "" {
+		version = "1.1"
+	}
+	return body, "application/x-amz-json-" + version, nil
+}
+
+// endpoint returns the URL requests are sent to.
+func (p *DeclarativeProber) endpoint() string {
+	if p.cfg.BaseEndpoint != nil && *p.cfg.BaseEndpoint != "" {
+		return *p.cfg.BaseEndpoint
+	}
+	if p.def.Endpoint != "" {
+		return p.def.Endpoint
+	}
+
+	prefix := p.def.EndpointPrefix
+	if prefix == "" {
+		prefix = p.def.Service
+	}
+
+	return fmt.Sprintf("https://%s.%s.%s", prefix, p.cfg.Region, awsprobe.DNSSuffix(p.cfg.Region))
+}
+
+// signingRegion returns the region used for SigV4.
+func (p *DeclarativeProber) signingRegion() string {
+	if p.def.SigningRegion != "" {
+		return p.def.SigningRegion
+	}
+	return p.cfg.Region
+}
+
+// decodeError converts an error response into a definitionError.
+func (p *DeclarativeProber) decodeError(resp *http.Response, body []byte) error {
+	apiErr := &definitionError{
+		StatusCode: resp.StatusCode,
+		Code:       http.StatusText(resp.StatusCode),
+	}
+
+	if p.def.Protocol == ProtocolQuery {
+		var envelope struct {
+			Error struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Error"`
+		}
+		if xml.Unmarshal(body, &envelope) == nil && envelope.Error.Code != "" {
+			apiErr.Code = envelope.Error.Code
+			apiErr.Message = envelope.Error.Message
+		}
+		return apiErr
+	}
+
+	var envelope struct {
+		Type         string `json:"__type"`
+		Message      string `json:"message"`
+		MessageUpper string `json:"Message"`
+	}
+	if json.Unmarshal(body, &envelope) == nil {
+		if envelope.Type != "" {
+			apiErr.Code = envelope.Type
+		}
+		apiErr.Message = envelope.Message
+		if apiErr.Message == "" {
+			apiErr.Message = envelope.MessageUpper
+		}
+	}
+	if header := resp.Header.Get("X-Amzn-Errortype"); header != "" {
+		apiErr.Code = header
+	}
+
+	// Codes may be qualified (namespace#Code) or carry a suffix (Code:uri).
+	if i := strings.LastIndex(apiErr.Code, "#"); i >= 0 {
+		apiErr.Code = apiErr.Code[i+1:]
+	}
+	if i := strings.Index(apiErr.Code, ":"); i >= 0 {
+		apiErr.Code = apiErr.Code[:i]
+	}
+
+	return apiErr
+}
+
+// definitionError is an AWS API error returned to a DeclarativeProber. It
+// implements smithy.APIError and exposes the HTTP status code so the SDK
+// retryer can classify throttling and server errors.
+type definitionError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *definitionError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api error %s (status %d)", e.Code, e.StatusCode)
+	}
+	return fmt.Sprintf("api error %s: %s (status %d)", e.Code, e.Message, e.StatusCode)
+}
+
+func (e *definitionError) ErrorCode() string    { return e.Code }
+func (e *definitionError) ErrorMessage() string { return e.Message }
+func (e *definitionError) HTTPStatusCode() int  { return e.StatusCode }
+
+func (e *definitionError) ErrorFault() smithy.ErrorFault {
+	if e.StatusCode >= 500 {
+		return smithy.FaultServer
+	}
+	return smithy.FaultClient
+}
+
+// decodeQueryResponse converts a query protocol XML response into a generic
+// document rooted at the <Operation>Result element.
+func decodeQueryResponse(body []byte, operation string) (any, error) {
+	root, err := decodeXML(body)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s response: %w", operation, err)
+	}
+
+	if m, ok := root.(map[string]any); ok {
+		if result, ok := m[operation+"Result"]; ok {
+			return result, nil
+		}
+	}
+	return root, nil
+}
+
+// decodeXML converts an XML document into maps, lists and strings. Elements
+// whose children are all <member> or <item> become lists; elements with
+// children become maps; everything else becomes its text content.
+func decodeXML(body []byte) (any, error) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := tok.(xml.StartElement); ok {
+			return decodeXMLElement(dec)
+		}
+	}
+}
+
+// xmlChild is a decoded child element, kept in document order.
+type xmlChild struct {
+	name  string
+	value any
+}
+
+// decodeXMLElement decodes the element whose start tag was just read.
+func decodeXMLElement(dec *xml.Decoder) (any, error) {
+	var children []xmlChild
+	var text strings.Builder
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			value, err := decodeXMLElement(dec)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, xmlChild{name: t.Name.Local, value: value})
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(children) == 0 {
+				return strings.TrimSpace(text.String()), nil
+			}
+
+			isList := true
+			for _, c := range children {
+				if c.name != "member" && c.name != "item" {
+					isList = false
+					break
+				}
+			}
+			if isList {
+				list := make([]any, len(children))
+				for i, c := range children {
+					list[i] = c.value
+				}
+				return list, nil
+			}
+
+			m := make(map[string]any, len(children))
+			for _, c := range children {
+				m[c.name] = c.value
+			}
+			return m, nil
+		}
+	}
+}
+
+// lookupPath walks a dotted path through maps and lists. An empty path
+// returns the document itself.
+func lookupPath(doc any, path string) (any, bool) {
+	if path == "" {
+		return doc, true
+	}
+
+	current := doc
+	for _, part := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case map[string]string:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// parseTags converts a tags value, either a map or a list of Key/Value
+// objects, into a string map.
+func parseTags(v any) map[string]string {
+	tags := make(map[string]string)
+
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			tags[k] = fmt.Sprint(val)
+		}
+	case []any:
+		for _, item := range t {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			key, value := tagField(m, "Key"), tagField(m, "Value")
+			if key != "" {
+				tags[key] = value
+			}
+		}
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// tagField reads a tag attribute case-insensitively (Key, key, TagKey).
+func tagField(m map[string]any, name string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if strings.EqualFold(k, name) || strings.EqualFold(k, "Tag"+name) {
+			return fmt.Sprint(m[k])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,434 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/smithy-go"
+
+	"github.com/shakefu/terraform-provider-probe/internal/faults"
+)
+
+// testDeclarativeConfig returns an AWS config pointed at server.
+func testDeclarativeConfig(server *httptest.Server) aws.Config {
+	return aws.Config{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+	}
+}
+
+func TestDeclarativeProber_JSONProtocol(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Amz-Target"); got != "Kinesis_20131202.DescribeStreamSummary" {
+			t.Errorf("unexpected X-Amz-Target %q", got)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/x-amz-json-1.1" {
+			t.Errorf("unexpected Content-Type %q", got)
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), "/us-east-1/kinesis/aws4_request") {
+			t.Errorf("request not signed for kinesis: %q", r.Header.Get("Authorization"))
+		}
+
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		if body["StreamName"] != "orders" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Stream not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"StreamDescriptionSummary":{"StreamName":"orders","StreamARN":"arn:aws:kinesis:us-east-1:123456789012:stream/orders","OpenShardCount":2}}`))
+	}))
+	defer server.Close()
+
+	prober := NewDeclarativeProber(testDeclarativeConfig(server), testKinesisStreamDefinition())
+
+	t.Run("exists", func(t *testing.T) {
+		result, err := prober.Probe(context.Background(), "orders")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists {
+			t.Fatal("expected Exists to be true")
+		}
+		if result.Arn != "arn:aws:kinesis:us-east-1:123456789012:stream/orders" {
+			t.Errorf("unexpected ARN %q", result.Arn)
+		}
+		if result.Properties["OpenShardCount"] != float64(2) {
+			t.Errorf("expected OpenShardCount=2, got %v", result.Properties["OpenShardCount"])
+		}
+		if result.TerraformType != "aws_kinesis_stream" || result.ImportID != "orders" {
+			t.Errorf("unexpected import target %s/%s", result.TerraformType, result.ImportID)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		result, err := prober.Probe(context.Background(), "missing")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Exists {
+			t.Error("expected Exists to be false")
+		}
+	})
+}
+
+const testGetRoleResponse = `<GetRoleResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <GetRoleResult>
+    <Role>
+      <RoleName>app</RoleName>
+      <Arn>arn:aws:iam::123456789012:role/app</Arn>
+      <Tags>
+        <member><Key>Team</Key><Value>platform</Value></member>
+        <member><Key>Env</Key><Value>prod</Value></member>
+      </Tags>
+    </Role>
+  </GetRoleResult>
+  <ResponseMetadata><RequestId>abc</RequestId></ResponseMetadata>
+</GetRoleResponse>`
+
+const testNoSuchEntityResponse = `<ErrorResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <Error><Type>Sender</Type><Code>NoSuchEntity</Code><Message>The role cannot be found.</Message></Error>
+</ErrorResponse>`
+
+func TestDeclarativeProber_QueryProtocol(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if r.Form.Get("Action") != "GetRole" || r.Form.Get("Version") != "2010-05-08" {
+			t.Errorf("unexpected query parameters: %v", r.Form)
+		}
+
+		w.Header().Set("Content-Type", "text/xml")
+		switch r.Form.Get("RoleName") {
+		case "app":
+			_, _ = w.Write([]byte(testGetRoleResponse))
+		case "denied":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>AccessDenied</Code><Message>nope</Message></Error></ErrorResponse>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(testNoSuchEntityResponse))
+		}
+	}))
+	defer server.Close()
+
+	prober := NewDeclarativeProber(testDeclarativeConfig(server), testIamRoleDefinition())
+
+	t.Run("exists with tags", func(t *testing.T) {
+		result, err := prober.Probe(context.Background(), "app")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists || result.Arn != "arn:aws:iam::123456789012:role/app" {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+		if result.Properties["RoleName"] != "app" {
+			t.Errorf("expected RoleName property, got %v", result.Properties)
+		}
+		if result.Tags["Team"] != "platform" || result.Tags["Env"] != "prod" {
+			t.Errorf("unexpected tags %v", result.Tags)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		result, err := prober.Probe(context.Background(), "ghost")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Exists {
+			t.Error("expected Exists to be false")
+		}
+	})
+
+	t.Run("other errors surface", func(t *testing.T) {
+		_, err := prober.Probe(context.Background(), "denied")
+		var apiErr smithy.APIError
+		if err == nil || !errors.As(err, &apiErr) || apiErr.ErrorCode() != "AccessDenied" {
+			t.Errorf("expected AccessDenied API error, got %v", err)
+		}
+	})
+}
+
+func TestDeclarativeProber_ArnTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		if r.FormValue("Action") == "GetCallerIdentity" {
+			_, _ = w.Write([]byte(`<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult><Account>210987654321</Account><Arn>arn:aws-us-gov:iam::210987654321:user/ci</Arn></GetCallerIdentityResult>
+</GetCallerIdentityResponse>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<GetRoleResponse><GetRoleResult><Role><RoleName>app</RoleName></Role></GetRoleResult></GetRoleResponse>`))
+	}))
+	defer server.Close()
+
+	cfg := testDeclarativeConfig(server)
+	cfg.Region = "us-gov-west-1"
+	def := testIamRoleDefinition()
+	def.ArnTemplate = "arn:${partition}:iam::${account}:role/${id}"
+
+	result, err := NewDeclarativeProber(cfg, def).Probe(context.Background(), "app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Arn != "arn:aws-us-gov:iam::210987654321:role/app" {
+		t.Errorf("unexpected ARN %q", result.Arn)
+	}
+}
+
+func TestDeclarativeProber_LifecycleState(t *testing.T) {
+	// Each stream is named after its status
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"StreamDescriptionSummary":{"StreamStatus":"` + body["StreamName"] + `"}}`))
+	}))
+	defer server.Close()
+
+	def := testKinesisStreamDefinition()
+	def.StatusPath = "StreamDescriptionSummary.StreamStatus"
+	def.LifecycleStates = map[string]string{"SUSPENDED": "archived"}
+	prober := NewDeclarativeProber(testDeclarativeConfig(server), def)
+
+	for _, tt := range []struct{ status, state string }{
+		{"DELETING", "deleting"},
+		{"SUSPENDED", "archived"},
+		{"ACTIVE", "active"},
+	} {
+		result, err := prober.Probe(context.Background(), tt.status)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Status != tt.status || result.Lifecycle() != tt.state {
+			t.Errorf("status %s: got %q, %q, want lifecycle state %q", tt.status, result.Status, result.Lifecycle(), tt.state)
+		}
+	}
+}
+
+func TestDeclarativeProber_RetriesThrottling(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"com.amazonaws.kinesis#ThrottlingException","message":"Rate exceeded"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"StreamDescriptionSummary":{"StreamName":"orders"}}`))
+	}))
+	defer server.Close()
+
+	prober := NewDeclarativeProber(testDeclarativeConfig(server), testKinesisStreamDefinition())
+	result, err := prober.Probe(context.Background(), "orders")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Exists {
+		t.Error("expected Exists to be true after retry")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls, got %d", calls.Load())
+	}
+}
+
+func TestDeclarativeProber_Faults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"StreamDescriptionSummary":{"StreamName":"orders"}}`))
+	}))
+	defer server.Close()
+
+	transport := faults.NewTransport(nil)
+	cfg := testDeclarativeConfig(server)
+	cfg.HTTPClient = transport.Client()
+	cfg.Retryer = func() aws.Retryer {
+		return retry.NewStandard(func(o *retry.StandardOptions) {
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) {
+				return 0, nil
+			})
+		})
+	}
+	prober := NewDeclarativeProber(cfg, testKinesisStreamDefinition())
+
+	t.Run("transient faults are retried", func(t *testing.T) {
+		for name, fault := range map[string]faults.Fault{
+			"unavailable":      faults.Unavailable(),
+			"throttled":        faults.Throttled(),
+			"connection reset": faults.ConnectionReset(),
+		} {
+			t.Run(name, func(t *testing.T) {
+				transport.Reset()
+				transport.InjectN("kinesis", "DescribeStreamSummary", 1, fault)
+
+				result, err := prober.Probe(context.Background(), "orders")
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !result.Exists {
+					t.Error("expected Exists to be true after retry")
+				}
+				if calls := transport.Calls("DescribeStreamSummary"); calls != 2 {
+					t.Errorf("expected 2 calls, got %d", calls)
+				}
+			})
+		}
+	})
+
+	t.Run("expired credentials are not retried", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("kinesis", "", faults.ExpiredCredentials())
+
+		_, err := prober.Probe(context.Background(), "orders")
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ExpiredTokenException" {
+			t.Fatalf("expected ExpiredTokenException, got %v", err)
+		}
+		if calls := transport.Calls("DescribeStreamSummary"); calls != 1 {
+			t.Errorf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("malformed body is an error", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("kinesis", "", faults.MalformedBody())
+
+		if _, err := prober.Probe(context.Background(), "orders"); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestDeclarativeProber_Endpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      aws.Config
+		def      ProberDefinition
+		expected string
+	}{
+		{
+			name:     "regional",
+			cfg:      aws.Config{Region: "eu-west-1"},
+			def:      ProberDefinition{Service: "kinesis"},
+			expected: "https://kinesis.eu-west-1.amazonaws.com",
+		},
+		{
+			name:     "endpoint prefix",
+			cfg:      aws.Config{Region: "us-east-1"},
+			def:      ProberDefinition{Service: "execute-api", EndpointPrefix: "apigateway"},
+			expected: "https://apigateway.us-east-1.amazonaws.com",
+		},
+		{
+			name:     "china partition",
+			cfg:      aws.Config{Region: "cn-north-1"},
+			def:      ProberDefinition{Service: "kinesis"},
+			expected: "https://kinesis.cn-north-1.amazonaws.com.cn",
+		},
+		{
+			name:     "iso partition",
+			cfg:      aws.Config{Region: "us-iso-east-1"},
+			def:      ProberDefinition{Service: "kinesis"},
+			expected: "https://kinesis.us-iso-east-1.c2s.ic.gov",
+		},
+		{
+			name:     "global endpoint",
+			cfg:      aws.Config{Region: "us-west-2"},
+			def:      ProberDefinition{Service: "iam", Endpoint: "https://iam.amazonaws.com"},
+			expected: "https://iam.amazonaws.com",
+		},
+		{
+			name:     "provider override wins",
+			cfg:      aws.Config{Region: "us-west-2", BaseEndpoint: aws.String("http://localhost:4566")},
+			def:      ProberDefinition{Service: "iam", Endpoint: "https://iam.amazonaws.com"},
+			expected: "http://localhost:4566",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewDeclarativeProber(tt.cfg, tt.def)
+			if got := p.endpoint(); got != tt.expected {
+				t.Errorf("endpoint() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeclarativeProber_ValidateIdentifier(t *testing.T) {
+	def := testIamRoleDefinition()
+	if err := NewDeclarativeProber(aws.Config{}, def).ValidateIdentifier("any thing"); err != nil {
+		t.Errorf("expected any identifier without a pattern, got %v", err)
+	}
+
+	def.IdentifierPattern = `^[\w+=,.@-]{1,64}$`
+	p := NewDeclarativeProber(aws.Config{}, def)
+	if err := p.ValidateIdentifier("deploy"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := p.ValidateIdentifier("deploy role"); err == nil || !strings.Contains(err.Error(), def.IdentifierPattern) {
+		t.Errorf("expected the pattern in the error, got %v", err)
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	doc := map[string]any{
+		"Table": map[string]any{
+			"Name": "t",
+			"Keys": []any{map[string]any{"Name": "pk"}},
+		},
+	}
+
+	tests := []struct {
+		path     string
+		expected any
+		found    bool
+	}{
+		{path: "Table.Name", expected: "t", found: true},
+		{path: "Table.Keys.0.Name", expected: "pk", found: true},
+		{path: "Table.Keys.1.Name", found: false},
+		{path: "Table.Missing", found: false},
+		{path: "Table.Name.Deeper", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := lookupPath(doc, tt.path)
+			if ok != tt.found || (ok && got != tt.expected) {
+				t.Errorf("lookupPath(%q) = %v, %v; want %v, %v", tt.path, got, ok, tt.expected, tt.found)
+			}
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	fromMap := parseTags(map[string]any{"Env": "prod"})
+	if fromMap["Env"] != "prod" {
+		t.Errorf("expected tags from map, got %v", fromMap)
+	}
+
+	fromList := parseTags([]any{
+		map[string]any{"TagKey": "Env", "TagValue": "prod"},
+		map[string]any{"key": "Team", "value": "platform"},
+		"garbage",
+	})
+	if fromList["Env"] != "prod" || fromList["Team"] != "platform" || len(fromList) != 2 {
+		t.Errorf("expected tags from list, got %v", fromList)
+	}
+
+	if parseTags("") != nil {
+		t.Error("expected nil tags for empty value")
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// Protocols supported by declarative probers.
+const (
+	// ProtocolJSON is the AWS JSON protocol (X-Amz-Target header, JSON body),
+	// used by services such as DynamoDB, Kinesis and CloudWatch Logs.
+	ProtocolJSON = "json"
+
+	// ProtocolQuery is the AWS query protocol (form-encoded Action parameter,
+	// XML response), used by services such as IAM, SNS and RDS.
+	ProtocolQuery = "query"
+)
+
+// ProberDefinition declares a prober as data instead of Go code. The prober
+// calls a single read operation with the identifier and maps the response
+// onto a probe.ProbeResult.
+type ProberDefinition struct {
+	// Type is the canonical Terraform-style type name (e.g., aws_iam_role).
+	Type string `json:"type"`
+
+	// Aliases are additional type names that resolve to Type
+	// (e.g., AWS::IAM::Role).
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Service is the SigV4 signing name (e.g., iam, kinesis).
+	Service string `json:"service"`
+
+	// EndpointPrefix is the hostname prefix of the regional endpoint.
+	// Defaults to Service.
+	EndpointPrefix string `json:"endpoint_prefix,omitempty"`
+
+	// Endpoint overrides the endpoint URL for services with a global
+	// endpoint (e.g., https://iam.amazonaws.com). A provider endpoint
+	// override still takes precedence.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// SigningRegion overrides the region used to sign requests, for global
+	// services signed in a fixed region.
+	SigningRegion string `json:"signing_region,omitempty"`
+
+	// Protocol is either "json" or "query".
+	Protocol string `json:"protocol"`
+
+	// TargetPrefix is the X-Amz-Target prefix for the JSON protocol
+	// (e.g., Kinesis_20131202).
+	TargetPrefix string `json:"target_prefix,omitempty"`
+
+	// JSONVersion is the JSON protocol version, "1.0" or "1.1" (default).
+	JSONVersion string `json:"json_version,omitempty"`
+
+	// APIVersion is the Version parameter for the query protocol
+	// (e.g., 2010-05-08).
+	APIVersion string `json:"api_version,omitempty"`
+
+	// Operation is the read operation to call (e.g., GetRole).
+	Operation string `json:"operation"`
+
+	// IdentifierParam is the request parameter that receives the identifier
+	// (e.g., RoleName).
+	IdentifierParam string `json:"identifier_param"`
+
+	// Parameters are additional static request parameters.
+	Parameters map[string]any `json:"parameters,omitempty"`
+
+	// NotFoundCodes are the error codes that mean the resource doesn't exist.
+	NotFoundCodes []string `json:"not_found_codes"`
+
+	// PropertiesPath is the dotted path of the object returned as properties.
+	// Defaults to the whole response.
+	PropertiesPath string `json:"properties_path,omitempty"`
+
+	// ArnPath is the dotted path of the resource ARN in the response.
+	ArnPath string `json:"arn_path,omitempty"`
+
+	// StatusPath is the dotted path of the resource status in the response.
+	StatusPath string `json:"status_path,omitempty"`
+
+	// LifecycleStates maps statuses to lifecycle states (e.g., Disabled:
+	// pending_deletion) where the common status names don't apply.
+	// Unlisted statuses are mapped by probe.LifecycleFromStatus.
+	LifecycleStates map[string]string `json:"lifecycle_states,omitempty"`
+
+	// ArnTemplate builds the resource ARN when the response doesn't include
+	// one, from ${partition}, ${region}, ${account} and ${id} placeholders
+	// (e.g., arn:${partition}:iam::${account}:role/${id}).
+	ArnTemplate string `json:"arn_template,omitempty"`
+
+	// TagsPath is the dotted path of the resource tags in the response,
+	// either a map or a list of Key/Value objects.
+	TagsPath string `json:"tags_path,omitempty"`
+
+	// ImmutableProperties names the properties that can't change after the
+	// resource is created, checked against the probe data source's desired
+	// argument.
+	ImmutableProperties []string `json:"immutable_properties,omitempty"`
+
+	// SensitiveProperties names the properties to keep out of plan output,
+	// such as policy documents.
+	SensitiveProperties []string `json:"sensitive_properties,omitempty"`
+
+	// IdentifierPattern is a regular expression identifiers must match,
+	// checked at plan time before any request is sent.
+	IdentifierPattern string `json:"identifier_pattern,omitempty"`
+}
+
+// proberDefinitionFile is the document format of a prober definitions file.
+type proberDefinitionFile struct {
+	Probers []ProberDefinition `json:"probers"`
+}
+
+// Validate checks that the definition has everything needed to run.
+func (d *ProberDefinition) Validate() error {
+	var problems []string
+
+	if d.Type == "" {
+		problems = append(problems, "type is required")
+	}
+	if d.Service == "" {
+		problems = append(problems, "service is required")
+	}
+	if d.Operation == "" {
+		problems = append(problems, "operation is required")
+	}
+	if d.IdentifierParam == "" {
+		problems = append(problems, "identifier_param is required")
+	}
+	if len(d.NotFoundCodes) == 0 {
+		problems = append(problems, "not_found_codes must list at least one error code")
+	}
+
+	if d.IdentifierPattern != "" {
+		if _, err := regexp.Compile(d.IdentifierPattern); err != nil {
+			problems = append(problems, fmt.Sprintf("identifier_pattern is invalid: %s", err))
+		}
+	}
+
+	statuses := make([]string, 0, len(d.LifecycleStates))
+	for status := range d.LifecycleStates {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		if state := d.LifecycleStates[status]; !slices.Contains(probe.LifecycleStates(), state) {
+			problems = append(problems, fmt.Sprintf("lifecycle_states maps %s to %q, which is not one of %s",
+				status, state, strings.Join(probe.LifecycleStates(), ", ")))
+		}
+	}
+
+	if err := validateARNTemplate(d.ArnTemplate); err != nil {
+		problems = append(problems, fmt.Sprintf("arn_template is invalid: %s", err))
+	}
+
+	switch d.Protocol {
+	case ProtocolJSON:
+		if d.TargetPrefix == "" {
+			problems = append(problems, "target_prefix is required for the json protocol")
+		}
+		if d.JSONVersion != "" && d.JSONVersion != "1.0" && d.JSONVersion != "1.1" {
+			problems = append(problems, fmt.Sprintf("json_version must be 1.0 or 1.1, got %q", d.JSONVersion))
+		}
+	case ProtocolQuery:
+		if d.APIVersion == "" {
+			problems = append(problems, "api_version is required for the query protocol")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("protocol must be %q or %q, got %q", ProtocolJSON, ProtocolQuery, d.Protocol))
+	}
+
+	if len(problems) > 0 {
+		name := d.Type
+		if name == "" {
+			name = "(unnamed)"
+		}
+		return fmt.Errorf("prober definition %s: %s", name, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// LoadProberDefinitions reads prober definitions from a JSON or YAML file, or
+// from every .json, .yaml and .yml file in a directory.
+func LoadProberDefinitions(path string) ([]ProberDefinition, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		for _, pattern := range []string{"*.json", "*.yaml", "*.yml"} {
+			matches, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, matches...)
+		}
+		sort.Strings(files)
+	}
+
+	var defs []ProberDefinition
+	seen := make(map[string]string)
+	var errs []error
+
+	for _, file := range files {
+		fileDefs, err := parseProberDefinitions(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+
+		for _, def := range fileDefs {
+			if err := def.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", file, err))
+				continue
+			}
+			if prev, ok := seen[def.Type]; ok {
+				errs = append(errs, fmt.Errorf("%s: prober definition %s is already defined in %s", file, def.Type, prev))
+				continue
+			}
+			seen[def.Type] = file
+			defs = append(defs, def)
+		}
+	}
+
+	return defs, errors.Join(errs...)
+}
+
+// parseProberDefinitions decodes a single definitions file. YAML files are
+// converted to JSON first so both formats share the json struct tags.
+func parseProberDefinitions(file string) ([]ProberDefinition, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if ext := filepath.Ext(file); ext == ".yaml" || ext == ".yml" {
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	var parsed proberDefinitionFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed.Probers, nil
+}
@@ -0,0 +1,265 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testIamRoleDefinition is a valid query protocol definition.
+func testIamRoleDefinition() ProberDefinition {
+	return ProberDefinition{
+		Type:            "aws_iam_role",
+		Aliases:         []string{"AWS::IAM::Role"},
+		Service:         "iam",
+		Protocol:        ProtocolQuery,
+		APIVersion:      "2010-05-08",
+		Operation:       "GetRole",
+		IdentifierParam: "RoleName",
+		NotFoundCodes:   []string{"NoSuchEntity"},
+		PropertiesPath:  "Role",
+		ArnPath:         "Role.Arn",
+		TagsPath:        "Role.Tags",
+	}
+}
+
+// testKinesisStreamDefinition is a valid JSON protocol definition.
+func testKinesisStreamDefinition() ProberDefinition {
+	return ProberDefinition{
+		Type:            "aws_kinesis_stream",
+		Service:         "kinesis",
+		Protocol:        ProtocolJSON,
+		TargetPrefix:    "Kinesis_20131202",
+		Operation:       "DescribeStreamSummary",
+		IdentifierParam: "StreamName",
+		NotFoundCodes:   []string{"ResourceNotFoundException"},
+		PropertiesPath:  "StreamDescriptionSummary",
+		ArnPath:         "StreamDescriptionSummary.StreamARN",
+	}
+}
+
+func TestProberDefinition_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(d *ProberDefinition)
+		wantErr string
+	}{
+		{
+			name:   "valid query definition",
+			modify: func(d *ProberDefinition) {},
+		},
+		{
+			name:    "missing type",
+			modify:  func(d *ProberDefinition) { d.Type = "" },
+			wantErr: "type is required",
+		},
+		{
+			name:    "missing not found codes",
+			modify:  func(d *ProberDefinition) { d.NotFoundCodes = nil },
+			wantErr: "not_found_codes",
+		},
+		{
+			name:    "unknown protocol",
+			modify:  func(d *ProberDefinition) { d.Protocol = "rest-xml" },
+			wantErr: `protocol must be "json" or "query"`,
+		},
+		{
+			name:    "invalid identifier pattern",
+			modify:  func(d *ProberDefinition) { d.IdentifierPattern = "[a-z" },
+			wantErr: "identifier_pattern is invalid",
+		},
+		{
+			name:    "unknown arn template placeholder",
+			modify:  func(d *ProberDefinition) { d.ArnTemplate = "arn:${partition}:iam::${account_id}:role/${id}" },
+			wantErr: "arn_template is invalid",
+		},
+		{
+			name:    "unknown lifecycle state",
+			modify:  func(d *ProberDefinition) { d.LifecycleStates = map[string]string{"Disabled": "disabled"} },
+			wantErr: `lifecycle_states maps Disabled to "disabled"`,
+		},
+		{
+			name:    "query without api version",
+			modify:  func(d *ProberDefinition) { d.APIVersion = "" },
+			wantErr: "api_version is required",
+		},
+		{
+			name: "json without target prefix",
+			modify: func(d *ProberDefinition) {
+				d.Protocol = ProtocolJSON
+				d.TargetPrefix = ""
+			},
+			wantErr: "target_prefix is required",
+		},
+		{
+			name: "json with invalid version",
+			modify: func(d *ProberDefinition) {
+				d.Protocol = ProtocolJSON
+				d.TargetPrefix = "Foo"
+				d.JSONVersion = "2.0"
+			},
+			wantErr: "json_version must be 1.0 or 1.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := testIamRoleDefinition()
+			tt.modify(&def)
+
+			err := def.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+const testDefinitionsJSON = `{
+  "probers": [
+    {
+      "type": "aws_kinesis_stream",
+      "service": "kinesis",
+      "protocol": "json",
+      "target_prefix": "Kinesis_20131202",
+      "operation": "DescribeStreamSummary",
+      "identifier_param": "StreamName",
+      "not_found_codes": ["ResourceNotFoundException"]
+    }
+  ]
+}`
+
+const testDefinitionsYAML = `probers:
+  - type: aws_iam_role
+    aliases: ["AWS::IAM::Role"]
+    service: iam
+    endpoint: https://iam.amazonaws.com
+    signing_region: us-east-1
+    protocol: query
+    api_version: "2010-05-08"
+    operation: GetRole
+    identifier_param: RoleName
+    not_found_codes: [NoSuchEntity]
+    arn_path: Role.Arn
+`
+
+func TestLoadProberDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "kinesis.json"), testDefinitionsJSON)
+	writeTestFile(t, filepath.Join(dir, "iam.yaml"), testDefinitionsYAML)
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "ignored")
+
+	t.Run("single JSON file", func(t *testing.T) {
+		defs, err := LoadProberDefinitions(filepath.Join(dir, "kinesis.json"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(defs) != 1 || defs[0].Type != "aws_kinesis_stream" {
+			t.Errorf("unexpected definitions: %+v", defs)
+		}
+	})
+
+	t.Run("single YAML file", func(t *testing.T) {
+		defs, err := LoadProberDefinitions(filepath.Join(dir, "iam.yaml"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(defs) != 1 {
+			t.Fatalf("expected 1 definition, got %d", len(defs))
+		}
+		if defs[0].APIVersion != "2010-05-08" || defs[0].Aliases[0] != "AWS::IAM::Role" {
+			t.Errorf("unexpected definition: %+v", defs[0])
+		}
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		defs, err := LoadProberDefinitions(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(defs) != 2 {
+			t.Errorf("expected 2 definitions, got %d", len(defs))
+		}
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		if _, err := LoadProberDefinitions(filepath.Join(dir, "nope.json")); err == nil {
+			t.Error("expected error for missing path")
+		}
+	})
+}
+
+func TestLoadProberDefinitions_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "unknown field",
+			files:   map[string]string{"a.json": `{"probers": [{"type": "x", "srevice": "typo"}]}`},
+			wantErr: `unknown field "srevice"`,
+		},
+		{
+			name:    "invalid definition",
+			files:   map[string]string{"a.json": `{"probers": [{"type": "aws_thing"}]}`},
+			wantErr: "prober definition aws_thing",
+		},
+		{
+			name: "duplicate type",
+			files: map[string]string{
+				"a.json": testDefinitionsJSON,
+				"b.json": testDefinitionsJSON,
+			},
+			wantErr: "already defined",
+		},
+		{
+			name:    "malformed YAML",
+			files:   map[string]string{"a.yml": "probers: [\n"},
+			wantErr: "a.yml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeTestFile(t, filepath.Join(dir, name), content)
+			}
+
+			_, err := LoadProberDefinitions(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// writeTestFile writes content to path, failing the test on error.
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestLoadProberDefinitions_Example(t *testing.T) {
+	defs, err := LoadProberDefinitions(filepath.Join("..", "..", "examples", "prober_definitions", "probers.yaml"))
+	if err != nil {
+		t.Fatalf("example definitions failed to load: %v", err)
+	}
+	if len(defs) == 0 {
+		t.Error("expected example definitions")
+	}
+}
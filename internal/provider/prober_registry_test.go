@@ -4,9 +4,13 @@
 package provider
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+	"github.com/shakefu/terraform-provider-probe/probe/awsprobe"
 )
 
 func TestNormalizeTypeName(t *testing.T) {
@@ -15,7 +19,7 @@ func TestNormalizeTypeName(t *testing.T) {
 		input    string
 		expected string
 	}{
-		// Direct mappings from normalizedTypes
+		// Direct mappings from the built-in registrations
 		{
 			name:     "terraform dynamodb",
 			input:    "aws_dynamodb_table",
@@ -98,9 +102,9 @@ func TestNormalizeTypeName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := normalizeTypeName(tt.input)
+			result := probe.DefaultCatalog().Normalize(tt.input)
 			if result != tt.expected {
-				t.Errorf("normalizeTypeName(%q) = %q, want %q", tt.input, result, tt.expected)
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, result, tt.expected)
 			}
 		})
 	}
@@ -108,7 +112,7 @@ func TestNormalizeTypeName(t *testing.T) {
 
 func TestProberRegistry_GetProber(t *testing.T) {
 	cfg := aws.Config{Region: "us-east-1"}
-	registry := NewProberRegistry(cfg)
+	registry := probe.NewProberRegistry(cfg)
 
 	t.Run("returns prober for supported type", func(t *testing.T) {
 		prober, err := registry.GetProber("aws_dynamodb_table")
@@ -162,7 +166,7 @@ func TestProberRegistry_GetProber(t *testing.T) {
 
 func TestProberRegistry_SupportedTypes(t *testing.T) {
 	cfg := aws.Config{Region: "us-east-1"}
-	registry := NewProberRegistry(cfg)
+	registry := probe.NewProberRegistry(cfg)
 
 	types := registry.SupportedTypes()
 
@@ -189,15 +193,74 @@ func TestProberRegistry_SupportedTypes(t *testing.T) {
 	}
 }
 
-func TestNewProberRegistry(t *testing.T) {
-	cfg := aws.Config{Region: "us-west-2"}
-	registry := NewProberRegistry(cfg)
+func TestRegisterDefinitions(t *testing.T) {
+	cfg := aws.Config{Region: "us-east-1"}
+	catalog := probe.DefaultCatalog().Clone()
+	RegisterDefinitions(catalog, []ProberDefinition{testIamRoleDefinition()})
+	registry := probe.NewProberRegistryWithCatalog(cfg, catalog)
 
-	if registry == nil {
-		t.Fatal("expected registry to be non-nil")
-	}
+	t.Run("resolves canonical type and aliases", func(t *testing.T) {
+		prober1, err := registry.GetProber("aws_iam_role")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := prober1.(*DeclarativeProber); !ok {
+			t.Fatalf("expected *DeclarativeProber, got %T", prober1)
+		}
 
-	if registry.probers == nil {
-		t.Error("expected probers map to be initialized")
-	}
+		prober2, err := registry.GetProber("AWS::IAM::Role")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if prober1 != prober2 {
+			t.Error("expected alias to return the same prober instance")
+		}
+	})
+
+	t.Run("gets cloudformation aliases", func(t *testing.T) {
+		RegisterDefinitions(catalog, []ProberDefinition{testKinesisStreamDefinition()})
+		if got := catalog.Aliases("aws_kinesis_stream"); !slices.Contains(got, "AWS::Kinesis::Stream") {
+			t.Errorf("expected the generated CloudFormation alias, got %v", got)
+		}
+		if got := catalog.Aliases("aws_iam_role"); len(got) != 1 || got[0] != "AWS::IAM::Role" {
+			t.Errorf("expected aliases without duplicates, got %v", got)
+		}
+	})
+
+	t.Run("listed in supported types", func(t *testing.T) {
+		found := false
+		for _, typ := range registry.SupportedTypes() {
+			if typ == "aws_iam_role" {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("expected aws_iam_role in supported types")
+		}
+	})
+
+	t.Run("definition replaces built-in prober", func(t *testing.T) {
+		builtin, _ := registry.GetProber("aws_s3_bucket")
+		if _, ok := builtin.(*awsprobe.S3Prober); !ok {
+			t.Fatalf("expected *awsprobe.S3Prober before override, got %T", builtin)
+		}
+
+		def := testKinesisStreamDefinition()
+		def.Type = "aws_s3_bucket"
+		RegisterDefinitions(catalog, []ProberDefinition{def})
+
+		prober, err := probe.NewProberRegistryWithCatalog(cfg, catalog).GetProber("AWS::S3::Bucket")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := prober.(*DeclarativeProber); !ok {
+			t.Errorf("expected *DeclarativeProber after override, got %T", prober)
+		}
+	})
+
+	t.Run("default catalog is unchanged", func(t *testing.T) {
+		if _, ok := probe.DefaultCatalog().Factory("aws_iam_role"); ok {
+			t.Error("expected aws_iam_role to be absent from the default catalog")
+		}
+	})
 }
@@ -1,26 +1,34 @@
 // Copyright (c) HashiCorp, Inc.
 // SPDX-License-Identifier: MPL-2.0
 
+// Package provider implements the Terraform provider for probing AWS resources.
 package provider
 
 import (
 	"context"
-	"net/http"
 	"os"
-	"time"
+	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
 )
 
 // Ensure ProbeProvider satisfies various provider interfaces.
-var _ provider.Provider = &ProbeProvider{}
+var (
+	_ provider.Provider                   = &ProbeProvider{}
+	_ provider.ProviderWithValidateConfig = &ProbeProvider{}
+)
 
 // ProbeProvider defines the provider implementation.
 type ProbeProvider struct {
@@ -28,13 +36,105 @@ type ProbeProvider struct {
 	// provider is built and run locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// catalog lists the resource types the provider can probe.
+	catalog *probe.Catalog
+
+	// httpClient, if set, replaces the HTTP client of the AWS config. Tests
+	// use it to inject faults.
+	httpClient aws.HTTPClient
+
+	// hints is shared with the provider's data sources and resources, which
+	// validate their configuration before the provider is configured.
+	hints *providerHints
+}
+
+// providerHints is what data sources and resources know about the provider
+// before it's configured: the catalog it was built with, and whether its
+// configuration may load prober_definitions that add to it.
+type providerHints struct {
+	catalog *probe.Catalog
+
+	mu          sync.Mutex
+	validated   bool
+	definitions bool
+}
+
+// registry returns a registry for the types the provider supports before
+// any prober_definitions are loaded. A nil h stands for the default catalog.
+func (h *providerHints) registry() *probe.ProberRegistry {
+	if h == nil || h.catalog == nil {
+		return probe.NewProberRegistry(aws.Config{})
+	}
+	return probe.NewProberRegistryWithCatalog(aws.Config{}, h.catalog)
+}
+
+// mayDefineTypes reports whether the provider configuration may load
+// prober_definitions, which is assumed until it has been validated.
+func (h *providerHints) mayDefineTypes() bool {
+	if h == nil {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return !h.validated || h.definitions
 }
 
 // ProbeProviderModel describes the provider data model.
 type ProbeProviderModel struct {
-	LocalStack types.Bool   `tfsdk:"localstack"`
-	Endpoint   types.String `tfsdk:"endpoint"`
-	Region     types.String `tfsdk:"region"`
+	LocalStack        types.Bool      `tfsdk:"localstack"`
+	Endpoint          types.String    `tfsdk:"endpoint"`
+	Emulator          types.String    `tfsdk:"emulator"`
+	Region            types.String    `tfsdk:"region"`
+	ProberDefinitions types.String    `tfsdk:"prober_definitions"`
+	Overrides         types.Dynamic   `tfsdk:"overrides"`
+	OverridesFile     types.String    `tfsdk:"overrides_file"`
+	OverridesStrict   types.Bool      `tfsdk:"overrides_strict"`
+	DropSensitive     types.Bool      `tfsdk:"drop_sensitive_properties"`
+	OnError           types.String    `tfsdk:"on_error"`
+	Ownership         *OwnershipModel `tfsdk:"ownership"`
+}
+
+// ProbeProviderData is passed from the provider to its data sources and
+// resources.
+type ProbeProviderData struct {
+	// Config is the resolved AWS configuration.
+	Config aws.Config
+
+	// Catalog lists the resource types data sources can probe, including
+	// declarative probers loaded from prober_definitions.
+	Catalog *probe.Catalog
+
+	// Emulator is the local emulator in use, or nil for AWS.
+	Emulator *EmulatorInfo
+
+	// OverridesStrict is set when every probe must be answered from
+	// overrides, so nothing may call AWS.
+	OverridesStrict bool
+
+	// Ownership is the provider's ownership rule, or nil if none is set.
+	Ownership *OwnershipRule
+
+	// DropSensitive is set when sensitive properties are left out of probe
+	// results instead of being reported in sensitive_properties.
+	DropSensitive bool
+
+	// OnError is the on_error policy for probes that don't set their own,
+	// or empty if it isn't set.
+	OnError string
+
+	// Registry holds the probers for Catalog, shared by every data source
+	// and resource so each prober's client is built once.
+	Registry *probe.ProberRegistry
+
+	// Clients pools the AWS clients data sources call directly and the
+	// accounts probers build ARNs with.
+	Clients *ClientPool
+
+	// UnknownConfig lists the provider arguments that weren't known when
+	// Terraform couldn't defer, in which case nothing else is set and probes
+	// can't run.
+	UnknownConfig []string
 }
 
 func (p *ProbeProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -47,21 +147,68 @@ func (p *ProbeProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 		Description: "The probe provider checks whether AWS resources exist without failing when they don't.",
 		Attributes: map[string]schema.Attribute{
 			"localstack": schema.BoolAttribute{
-				Description: "Explicitly enable or disable LocalStack detection. If not set, auto-detects LocalStack at localhost:4566.",
+				Description: "Explicitly enable or disable LocalStack detection. If not set, auto-detects LocalStack at AWS_ENDPOINT_URL, LOCALSTACK_HOST, or localhost:4566.",
 				Optional:    true,
 			},
 			"endpoint": schema.StringAttribute{
 				Description: "Override the AWS endpoint URL. Setting this implies localstack = true.",
 				Optional:    true,
 			},
+			"emulator": schema.StringAttribute{
+				Description: "Local emulator to target: " + strings.Join(EmulatorNames(), ", ") + ". Selects the emulator's default endpoint, health check, credentials and known API gaps. Conflicts with localstack.",
+				Optional:    true,
+			},
 			"region": schema.StringAttribute{
 				Description: "AWS region. Defaults to AWS_REGION environment variable, then us-east-1.",
 				Optional:    true,
 			},
+			"prober_definitions": schema.StringAttribute{
+				Description: "Path to a JSON or YAML file, or a directory of them, declaring additional resource types to probe.",
+				Optional:    true,
+			},
+			"overrides": schema.DynamicAttribute{
+				Description: "Canned probe results keyed by \"type/id\", each an object with exists (default true), arn, status, lifecycle_state, properties, tags, terraform_type and import_id. Matching probes make no AWS calls. Conflicts with overrides_file.",
+				Optional:    true,
+			},
+			"overrides_file": schema.StringAttribute{
+				Description: "Path to a JSON file of overrides in the same format as overrides.",
+				Optional:    true,
+			},
+			"overrides_strict": schema.BoolAttribute{
+				Description: "Fail any probe not covered by overrides instead of calling AWS, and skip LocalStack auto-detection. Defaults to false.",
+				Optional:    true,
+			},
+			"drop_sensitive_properties": schema.BoolAttribute{
+				Description: "Leave properties probers classify as sensitive out of probe results entirely, instead of reporting them in sensitive_properties. Defaults to false.",
+				Optional:    true,
+			},
+			"on_error": schema.StringAttribute{
+				Description: "What probe data sources do when a probe fails unexpectedly: " + strings.Join(OnErrorPolicies(), ", ") + ". Data sources may set their own. Defaults to fail.",
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"ownership": providerOwnershipBlock(),
 		},
 	}
 }
 
+// ValidateConfig records whether the configuration sets prober_definitions,
+// so data sources and resources know whether a type missing from the catalog
+// may still be defined.
+func (p *ProbeProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var definitions types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("prober_definitions"), &definitions)...)
+	if resp.Diagnostics.HasError() || p.hints == nil {
+		return
+	}
+
+	p.hints.mu.Lock()
+	defer p.hints.mu.Unlock()
+	p.hints.validated = true
+	p.hints.definitions = !definitions.IsNull()
+}
+
 func (p *ProbeProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data ProbeProviderModel
 
@@ -70,41 +217,52 @@ func (p *ProbeProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	// Determine region
-	region := "us-east-1"
-	if !data.Region.IsNull() {
-		region = data.Region.ValueString()
-	} else if envRegion := os.Getenv("AWS_REGION"); envRegion != "" {
-		region = envRegion
-	} else if envRegion := os.Getenv("AWS_DEFAULT_REGION"); envRegion != "" {
-		region = envRegion
+	// A region or endpoint from a resource that doesn't exist yet can't
+	// build an AWS config. Defer everything until apply if Terraform allows
+	// it; otherwise probes report the unknown arguments when they run.
+	if unknown := unknownAttributes(req.Config.Raw); len(unknown) > 0 {
+		tflog.Info(ctx, "Provider configuration is unknown", map[string]any{"attributes": unknown})
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		providerData := &ProbeProviderData{UnknownConfig: unknown}
+		resp.DataSourceData = providerData
+		resp.ResourceData = providerData
+		return
 	}
 
-	// Determine if using LocalStack
-	useLocalStack := false
-	endpoint := ""
+	settings := AWSSettings{
+		Region:   data.Region.ValueString(),
+		Endpoint: data.Endpoint.ValueString(),
+		Emulator: data.Emulator.ValueString(),
+	}
+	if !data.LocalStack.IsNull() {
+		settings.LocalStack = data.LocalStack.ValueBoolPointer()
+	}
 
-	if !data.Endpoint.IsNull() {
-		// Explicit endpoint implies LocalStack
-		useLocalStack = true
-		endpoint = data.Endpoint.ValueString()
-	} else if !data.LocalStack.IsNull() {
-		// Explicit LocalStack setting
-		useLocalStack = data.LocalStack.ValueBool()
-		if useLocalStack {
-			endpoint = "http://localhost:4566"
-		}
-	} else {
-		// Auto-detect LocalStack
-		detected, detectedEndpoint := detectLocalStack()
-		useLocalStack = detected
-		endpoint = detectedEndpoint
+	if err := validateEmulatorSettings(settings); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("emulator"),
+			"Invalid emulator",
+			err.Error(),
+		)
+		return
 	}
 
-	// Load AWS config
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(region),
-	)
+	resp.Diagnostics.Append(validateOnError(path.Root("on_error"), data.OnError)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	strict := data.OverridesStrict.ValueBool()
+	if strict && settings.LocalStack == nil && settings.Endpoint == "" && settings.Emulator == "" {
+		// Auto-detection would call the network
+		disabled := false
+		settings.LocalStack = &disabled
+	}
+
+	cfg, emulator, err := loadAWSConfig(ctx, settings)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to load AWS configuration",
@@ -113,50 +271,208 @@ func (p *ProbeProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	// Configure for LocalStack if needed
-	if useLocalStack {
-		cfg.BaseEndpoint = aws.String(endpoint)
-		// Use dummy credentials if none are configured
-		if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
-			cfg.Credentials = credentials.NewStaticCredentialsProvider("test", "test", "")
+	if p.httpClient != nil {
+		cfg.HTTPClient = p.httpClient
+	}
+
+	if err := useCassette(&cfg); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid cassette configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	providerData := &ProbeProviderData{
+		Config:          cfg,
+		Catalog:         p.catalog,
+		Emulator:        emulator,
+		OverridesStrict: strict,
+		DropSensitive:   data.DropSensitive.ValueBool(),
+		OnError:         data.OnError.ValueString(),
+	}
+
+	if !data.ProberDefinitions.IsNull() {
+		defs, err := LoadProberDefinitions(data.ProberDefinitions.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("prober_definitions"),
+				"Invalid prober definitions",
+				err.Error(),
+			)
+			return
+		}
+		providerData.Catalog = p.catalog.Clone()
+		RegisterDefinitions(providerData.Catalog, defs)
+	}
+
+	if data.Ownership != nil {
+		if data.Ownership.TagKey.IsNull() || data.Ownership.ExpectedValue.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ownership"),
+				"Incomplete ownership rule",
+				"The provider ownership block needs both tag_key and expected_value.",
+			)
+			return
 		}
+		rule, _ := data.Ownership.merge(nil)
+		providerData.Ownership = &rule
+	}
+
+	overrides, diags := p.loadOverrides(ctx, data, providerData.Catalog)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	if overrides != nil || strict {
+		providerData.Catalog = ApplyOverrides(providerData.Catalog, overrides, strict)
+	}
+	providerData.Registry = probe.NewProberRegistryWithCatalog(providerData.Config, providerData.Catalog)
+	providerData.Clients = NewClientPool()
 
-	// Make the AWS config available to data sources
-	resp.DataSourceData = cfg
+	// Make the AWS config and probers available to data sources and
+	// resources
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+}
+
+// loadOverrides reads the overrides or overrides_file setting. It returns nil
+// if neither is set.
+func (p *ProbeProvider) loadOverrides(ctx context.Context, data ProbeProviderModel, catalog *probe.Catalog) (Overrides, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	inline := !data.Overrides.IsNull() && !data.Overrides.IsUnderlyingValueNull()
+	switch {
+	case inline && !data.OverridesFile.IsNull():
+		diags.AddAttributeError(
+			path.Root("overrides"),
+			"Conflicting overrides",
+			"Set only one of overrides and overrides_file.",
+		)
+		return nil, diags
+	case !data.OverridesFile.IsNull():
+		overrides, err := LoadOverrides(data.OverridesFile.ValueString(), catalog)
+		if err != nil {
+			diags.AddAttributeError(path.Root("overrides_file"), "Invalid overrides", err.Error())
+			return nil, diags
+		}
+		return overrides, diags
+	case !inline:
+		return nil, diags
+	}
+
+	overrides, err := inlineOverrides(ctx, data.Overrides, catalog)
+	if err != nil {
+		diags.AddAttributeError(path.Root("overrides"), "Invalid overrides", err.Error())
+		return nil, diags
+	}
+	return overrides, diags
 }
 
 func (p *ProbeProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return nil
+	return []func() resource.Resource{
+		func() resource.Resource { return &ProbeAdoptionResource{hints: p.hints} },
+		func() resource.Resource { return &ProbeWaitResource{hints: p.hints} },
+	}
 }
 
 func (p *ProbeProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
-		NewProbeDataSource,
+		func() datasource.DataSource { return &ProbeDataSource{hints: p.hints} },
 		NewIamPolicySimulationDataSource,
+		NewSupportedTypesDataSource,
 	}
 }
 
 // New creates a new provider instance.
 func New(version string) func() provider.Provider {
+	return NewWithCatalog(version, probe.DefaultCatalog())
+}
+
+// NewWithCatalog creates a new provider instance that probes the resource
+// types in catalog.
+func NewWithCatalog(version string, catalog *probe.Catalog) func() provider.Provider {
 	return func() provider.Provider {
 		return &ProbeProvider{
 			version: version,
+			catalog: catalog,
+			hints:   &providerHints{catalog: catalog},
 		}
 	}
 }
 
-// detectLocalStack probes for LocalStack at the default endpoint.
-func detectLocalStack() (bool, string) {
-	client := &http.Client{Timeout: 500 * time.Millisecond}
-	resp, err := client.Get("http://localhost:4566/_localstack/health")
+// AWSSettings holds the inputs used to resolve an AWS configuration. Zero
+// values mean "not set", so the same environment fallbacks and LocalStack
+// auto-detection apply as for an empty provider block.
+type AWSSettings struct {
+	// LocalStack explicitly enables or disables LocalStack. Nil auto-detects.
+	LocalStack *bool
+
+	// Endpoint overrides the AWS endpoint URL. Without Emulator, it implies
+	// LocalStack.
+	Endpoint string
+
+	// Emulator selects a local emulator profile (see EmulatorNames). Empty
+	// means LocalStack or AWS, depending on LocalStack and Endpoint.
+	Emulator string
+
+	// Region is the AWS region. Empty falls back to AWS_REGION,
+	// AWS_DEFAULT_REGION, then us-east-1.
+	Region string
+}
+
+// LoadAWSConfig resolves region, LocalStack detection and endpoint the same
+// way the provider does, so probes behave identically inside and outside
+// Terraform.
+func LoadAWSConfig(ctx context.Context, settings AWSSettings) (aws.Config, error) {
+	cfg, _, err := loadAWSConfig(ctx, settings)
+	return cfg, err
+}
+
+// loadAWSConfig is LoadAWSConfig that also returns the emulator in use, or
+// nil if requests go to AWS.
+func loadAWSConfig(ctx context.Context, settings AWSSettings) (aws.Config, *EmulatorInfo, error) {
+	// Determine region
+	region := "us-east-1"
+	if settings.Region != "" {
+		region = settings.Region
+	} else if envRegion := os.Getenv("AWS_REGION"); envRegion != "" {
+		region = envRegion
+	} else if envRegion := os.Getenv("AWS_DEFAULT_REGION"); envRegion != "" {
+		region = envRegion
+	}
+
+	// Determine if using an emulator
+	emulator, err := resolveEmulator(ctx, settings)
 	if err != nil {
-		return false, ""
+		return aws.Config{}, nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusOK {
-		return true, "http://localhost:4566"
+	if emulator != nil {
+		tflog.Info(ctx, "Using emulator", map[string]any{
+			"emulator": emulator.Name,
+			"endpoint": emulator.Endpoint,
+			"edition":  emulator.Edition,
+			"version":  emulator.Version,
+			"services": emulator.Services,
+		})
+	} else {
+		tflog.Debug(ctx, "No emulator in use", map[string]any{
+			"localstack_endpoint": localStackEndpoint(),
+		})
 	}
-	return false, ""
+
+	// Load AWS config
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+	)
+	if err != nil {
+		return aws.Config{}, nil, err
+	}
+
+	if emulator != nil {
+		configureEmulator(ctx, &cfg, emulator)
+	}
+
+	return cfg, emulator, nil
 }
@@ -4,73 +4,240 @@
 package provider
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/shakefu/terraform-provider-probe/internal/cassette"
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/internal/faults"
+	"github.com/shakefu/terraform-provider-probe/probe/awsprobe"
 )
 
-func TestDetectLocalStack_Available(t *testing.T) {
-	// Create a mock server that responds like LocalStack
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/_localstack/health" {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"services": {}}`))
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
-
-	// The actual detectLocalStack function hardcodes localhost:4566,
-	// so we can't easily test it with a mock server.
-	// Instead, we test the logic by calling it and accepting the result
-	// since localhost:4566 may or may not be running.
-	detected, endpoint := detectLocalStack()
-
-	// If LocalStack happens to be running, both should be set
-	// If not, both should be empty/false
-	if detected && endpoint == "" {
-		t.Error("detectLocalStack() returned detected=true but empty endpoint")
+func TestNew(t *testing.T) {
+	factory := New("test-version")
+	p := factory()
+
+	probeProvider, ok := p.(*ProbeProvider)
+	if !ok {
+		t.Fatalf("New() did not return *ProbeProvider, got %T", p)
 	}
-	if !detected && endpoint != "" {
-		t.Errorf("detectLocalStack() returned detected=false but endpoint=%q", endpoint)
+
+	if probeProvider.version != "test-version" {
+		t.Errorf("provider version = %q, want %q", probeProvider.version, "test-version")
 	}
 }
 
-func TestDetectLocalStack_NotAvailable(t *testing.T) {
-	// This test verifies the function handles connection failures gracefully.
-	// Since detectLocalStack() uses a hardcoded endpoint (localhost:4566),
-	// we can't inject a mock. We rely on the fact that if LocalStack isn't
-	// running, it should return (false, "").
-	//
-	// In CI environments without LocalStack, this is the expected behavior.
-	// The test primarily ensures no panic or unexpected error occurs.
-	detected, endpoint := detectLocalStack()
+func TestLoadAWSConfig(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	t.Run("explicit region wins over environment", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "eu-west-1")
+		disabled := false
+
+		cfg, err := LoadAWSConfig(context.Background(), AWSSettings{Region: "us-west-2", LocalStack: &disabled})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Region != "us-west-2" {
+			t.Errorf("expected region us-west-2, got %q", cfg.Region)
+		}
+		if cfg.BaseEndpoint != nil {
+			t.Errorf("expected no endpoint override, got %q", *cfg.BaseEndpoint)
+		}
+	})
+
+	t.Run("region falls back to environment", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "")
+		t.Setenv("AWS_DEFAULT_REGION", "ap-southeast-2")
+		disabled := false
+
+		cfg, err := LoadAWSConfig(context.Background(), AWSSettings{LocalStack: &disabled})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Region != "ap-southeast-2" {
+			t.Errorf("expected region ap-southeast-2, got %q", cfg.Region)
+		}
+	})
+
+	t.Run("endpoint implies localstack credentials", func(t *testing.T) {
+		cfg, err := LoadAWSConfig(context.Background(), AWSSettings{Endpoint: "http://localhost:9999"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.BaseEndpoint == nil || *cfg.BaseEndpoint != "http://localhost:9999" {
+			t.Errorf("expected endpoint override, got %v", cfg.BaseEndpoint)
+		}
+		if _, err := cfg.Credentials.Retrieve(context.Background()); err != nil {
+			t.Errorf("expected credentials to be available, got %v", err)
+		}
+	})
+}
+
+func TestUseCassette(t *testing.T) {
+	path := filepath.Join("..", "..", "probe", "awsprobe", "testdata", "cassettes", "s3_bucket.json")
+
+	t.Run("ignored outside acceptance tests", func(t *testing.T) {
+		t.Setenv("TF_ACC", "")
+		t.Setenv(EnvCassette, path)
+
+		var cfg aws.Config
+		if err := useCassette(&cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.HTTPClient != nil {
+			t.Error("expected HTTP client to be left unchanged")
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		t.Setenv("TF_ACC", "1")
+		t.Setenv(EnvCassette, path)
+		t.Setenv(EnvCassetteMode, "")
+
+		cfg := aws.Config{Region: "us-east-1"}
+		if err := useCassette(&cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, err := awsprobe.NewS3Prober(cfg).Probe(context.Background(), "assets")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists {
+			t.Error("expected replayed bucket to exist")
+		}
+	})
+
+	t.Run("record", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		recordPath := filepath.Join(t.TempDir(), "recorded.json")
+		t.Setenv("TF_ACC", "1")
+		t.Setenv(EnvCassette, recordPath)
+		t.Setenv(EnvCassetteMode, "record")
+
+		var cfg aws.Config
+		if err := useCassette(&cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
-	// We just verify the return values are consistent
-	t.Logf("detectLocalStack() returned detected=%v, endpoint=%q", detected, endpoint)
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := cfg.HTTPClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
 
-	if detected {
-		if endpoint != "http://localhost:4566" {
-			t.Errorf("detectLocalStack() detected=true but unexpected endpoint=%q", endpoint)
+		if _, err := os.Stat(recordPath); err != nil {
+			t.Errorf("expected cassette to be written: %v", err)
 		}
-	} else {
-		if endpoint != "" {
-			t.Errorf("detectLocalStack() detected=false but endpoint=%q", endpoint)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		t.Setenv("TF_ACC", "1")
+		t.Setenv(EnvCassette, path)
+		t.Setenv(EnvCassetteMode, "rewind")
+
+		var cfg aws.Config
+		if err := useCassette(&cfg); err == nil {
+			t.Error("expected error for invalid mode")
 		}
+	})
+}
+
+func getLocalStackConfig(t *testing.T) *aws.Config {
+	t.Helper()
+
+	localStack := detectLocalStack(context.Background())
+	if localStack == nil {
+		return nil
 	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion("us-east-1"),
+	)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	cfg.BaseEndpoint = aws.String(localStack.Endpoint)
+	cfg.Credentials = credentials.NewStaticCredentialsProvider("test", "test", "")
+
+	return &cfg
 }
 
-func TestNew(t *testing.T) {
-	factory := New("test-version")
-	p := factory()
+func getFakeAWSConfig(t *testing.T) (*fakeaws.Server, aws.Config) {
+	t.Helper()
 
-	probeProvider, ok := p.(*ProbeProvider)
-	if !ok {
-		t.Fatalf("New() did not return *ProbeProvider, got %T", p)
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	server := fakeaws.New(t)
+	cfg, err := LoadAWSConfig(context.Background(), AWSSettings{
+		Endpoint: server.URL,
+		Region:   fakeaws.Region,
+	})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
 	}
 
-	if probeProvider.version != "test-version" {
-		t.Errorf("provider version = %q, want %q", probeProvider.version, "test-version")
+	cfg.Retryer = func() aws.Retryer {
+		return retry.NewStandard(func(o *retry.StandardOptions) {
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) {
+				return 0, nil
+			})
+		})
+	}
+
+	return server, cfg
+}
+
+func getFaultConfig(t *testing.T) (*fakeaws.Server, *faults.Transport, aws.Config) {
+	t.Helper()
+
+	server, cfg := getFakeAWSConfig(t)
+	transport := faults.NewTransport(nil)
+	cfg.HTTPClient = transport.Client()
+
+	return server, transport, cfg
+}
+
+func getCassetteConfig(t *testing.T, name string) aws.Config {
+	t.Helper()
+
+	replayer, err := cassette.LoadReplayer(filepath.Join("testdata", "cassettes", name+".json"))
+	if err != nil {
+		t.Fatalf("failed to load cassette: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, unused := range replayer.Unused() {
+			t.Errorf("cassette %s: interaction not replayed: %s %s %s", name, unused.Request.Method, unused.Request.URL, unused.Request.Target)
+		}
+	})
+
+	return aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("test", "test", ""),
+		HTTPClient:  &http.Client{Transport: replayer},
+		Retryer: func() aws.Retryer {
+			return aws.NopRetryer{}
+		},
 	}
 }
@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// sensitiveKeys returns the sensitive property keys prober declares.
+func sensitiveKeys(prober probe.ResourceProber) []string {
+	if sp, ok := prober.(probe.SensitiveProber); ok {
+		return sp.SensitiveProperties()
+	}
+	return nil
+}
+
+// splitSensitive moves the properties named by keys out of props. It returns
+// the remaining properties and the sensitive ones, or nil if props has none
+// of keys; props itself is left alone.
+func splitSensitive(props map[string]any, keys []string) (public, sensitive map[string]any) {
+	for _, key := range keys {
+		value, ok := props[key]
+		if !ok {
+			continue
+		}
+		if sensitive == nil {
+			sensitive = make(map[string]any, len(keys))
+		}
+		sensitive[key] = value
+	}
+	if sensitive == nil {
+		return props, nil
+	}
+
+	public = make(map[string]any, len(props)-len(sensitive))
+	for key, value := range props {
+		if _, ok := sensitive[key]; !ok {
+			public[key] = value
+		}
+	}
+	return public, sensitive
+}
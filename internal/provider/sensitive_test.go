@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+func TestSplitSensitive(t *testing.T) {
+	props := map[string]any{
+		"RoleName":                 "deploy",
+		"AssumeRolePolicyDocument": `{"Version":"2012-10-17"}`,
+	}
+
+	public, sensitive := splitSensitive(props, []string{"AssumeRolePolicyDocument", "PermissionsBoundary"})
+	if !reflect.DeepEqual(public, map[string]any{"RoleName": "deploy"}) {
+		t.Errorf("unexpected public properties %v", public)
+	}
+	if !reflect.DeepEqual(sensitive, map[string]any{"AssumeRolePolicyDocument": `{"Version":"2012-10-17"}`}) {
+		t.Errorf("unexpected sensitive properties %v", sensitive)
+	}
+	if len(props) != 2 {
+		t.Error("splitSensitive modified its argument")
+	}
+
+	public, sensitive = splitSensitive(props, nil)
+	if sensitive != nil || !reflect.DeepEqual(public, props) {
+		t.Errorf("expected no sensitive properties, got %v, %v", public, sensitive)
+	}
+}
+
+func TestProbeDataSource_SensitiveProperties(t *testing.T) {
+	def := testIamRoleDefinition()
+	def.SensitiveProperties = []string{"AssumeRolePolicyDocument"}
+
+	catalog := probe.DefaultCatalog().Clone()
+	RegisterDefinitions(catalog, []ProberDefinition{def})
+	catalog = ApplyOverrides(catalog, Overrides{
+		"aws_iam_role": {
+			"deploy": {Properties: map[string]any{
+				"RoleName":                 "deploy",
+				"AssumeRolePolicyDocument": `{"Version":"2012-10-17"}`,
+			}},
+		},
+	}, true)
+	registry := probe.NewProberRegistryWithCatalog(aws.Config{Region: "us-east-1"}, catalog)
+
+	values := map[string]tftypes.Value{
+		"type": tftypes.NewValue(tftypes.String, "AWS::IAM::Role"),
+		"id":   tftypes.NewValue(tftypes.String, "deploy"),
+	}
+
+	t.Run("reported separately", func(t *testing.T) {
+		data, diags := readProbe(t, &ProbeDataSource{registry: registry}, values)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if got := data.PropertiesJSON.ValueString(); got != `{"RoleName":"deploy"}` {
+			t.Errorf("properties_json = %s", got)
+		}
+		if data.Sensitive.IsNull() {
+			t.Fatal("expected sensitive_properties")
+		}
+		policy, err := data.Sensitive.UnderlyingValue().ToTerraformValue(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		raw, _ := tftypesToGo(policy)
+		if !reflect.DeepEqual(raw, map[string]any{"AssumeRolePolicyDocument": `{"Version":"2012-10-17"}`}) {
+			t.Errorf("unexpected sensitive_properties %v", raw)
+		}
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		data, diags := readProbe(t, &ProbeDataSource{registry: registry, dropSensitive: true}, values)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if !data.Sensitive.IsNull() {
+			t.Errorf("expected null sensitive_properties, got %v", data.Sensitive)
+		}
+		if got := data.PropertiesJSON.ValueString(); got != `{"RoleName":"deploy"}` {
+			t.Errorf("properties_json = %s", got)
+		}
+	})
+
+	t.Run("schema marks it sensitive", func(t *testing.T) {
+		var resp datasource.SchemaResponse
+		(&ProbeDataSource{}).Schema(context.Background(), datasource.SchemaRequest{}, &resp)
+		if !resp.Schema.Attributes["sensitive_properties"].IsSensitive() {
+			t.Error("expected sensitive_properties to be sensitive")
+		}
+	})
+}
+
+func TestProbeWaitResource_SensitiveProperties(t *testing.T) {
+	def := testIamRoleDefinition()
+	def.SensitiveProperties = []string{"AssumeRolePolicyDocument"}
+
+	catalog := probe.DefaultCatalog().Clone()
+	RegisterDefinitions(catalog, []ProberDefinition{def})
+	catalog = ApplyOverrides(catalog, Overrides{
+		"aws_iam_role": {
+			"deploy": {Properties: map[string]any{
+				"RoleName":                 "deploy",
+				"AssumeRolePolicyDocument": `{"Version":"2012-10-17"}`,
+			}},
+		},
+	}, true)
+	registry := probe.NewProberRegistryWithCatalog(aws.Config{Region: "us-east-1"}, catalog)
+
+	values := map[string]tftypes.Value{
+		"type": tftypes.NewValue(tftypes.String, "aws_iam_role"),
+		"id":   tftypes.NewValue(tftypes.String, "deploy"),
+	}
+	public := func(t *testing.T, data ProbeWaitResourceModel) any {
+		t.Helper()
+		raw, err := data.Properties.UnderlyingValue().ToTerraformValue(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		props, _ := tftypesToGo(raw)
+		return props
+	}
+
+	t.Run("reported separately", func(t *testing.T) {
+		data, diags := createWait(t, &ProbeWaitResource{registry: registry}, values)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if props := public(t, data); !reflect.DeepEqual(props, map[string]any{"RoleName": "deploy"}) {
+			t.Errorf("unexpected properties %v", props)
+		}
+		if data.Sensitive.IsNull() {
+			t.Error("expected sensitive_properties")
+		}
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		data, diags := createWait(t, &ProbeWaitResource{registry: registry, dropSensitive: true}, values)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if props := public(t, data); !reflect.DeepEqual(props, map[string]any{"RoleName": "deploy"}) {
+			t.Errorf("unexpected properties %v", props)
+		}
+		if !data.Sensitive.IsNull() {
+			t.Errorf("expected null sensitive_properties, got %v", data.Sensitive)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// Backends report how a resource type is probed.
+const (
+	// BackendNative means a prober compiled into the provider calls the AWS
+	// SDK.
+	BackendNative = "native"
+
+	// BackendDeclarative means a prober_definitions entry describes the AWS
+	// call.
+	BackendDeclarative = "declarative"
+
+	// BackendOverride means overrides_strict answers every probe offline.
+	BackendOverride = "override"
+)
+
+// Ensure SupportedTypesDataSource satisfies datasource interfaces.
+var _ datasource.DataSource = &SupportedTypesDataSource{}
+var _ datasource.DataSourceWithConfigure = &SupportedTypesDataSource{}
+
+// SupportedTypesDataSource implements the probe_supported_types data source.
+type SupportedTypesDataSource struct {
+	registry *probe.ProberRegistry
+
+	// unknownConfig lists the provider arguments that weren't known when
+	// the provider was configured.
+	unknownConfig []string
+}
+
+// SupportedTypesDataSourceModel describes the data source data model.
+type SupportedTypesDataSourceModel struct {
+	Names types.List `tfsdk:"names"`
+	Types types.List `tfsdk:"types"`
+}
+
+// supportedTypeAttrTypes returns the attribute types of a types element.
+func supportedTypeAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"name":    types.StringType,
+		"aliases": types.ListType{ElemType: types.StringType},
+		"backend": types.StringType,
+	}
+}
+
+// NewSupportedTypesDataSource creates a new data source instance.
+func NewSupportedTypesDataSource() datasource.DataSource {
+	return &SupportedTypesDataSource{}
+}
+
+func (d *SupportedTypesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_supported_types"
+}
+
+func (d *SupportedTypesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the resource types the probe data source supports in this provider configuration.",
+
+		Attributes: map[string]schema.Attribute{
+			"names": schema.ListAttribute{
+				Description: "Canonical names of the supported types, sorted.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"types": schema.ListNestedAttribute{
+				Description: "Supported types, sorted by name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Canonical Terraform-style name (e.g., aws_dynamodb_table).",
+							Computed:    true,
+						},
+						"aliases": schema.ListAttribute{
+							Description: "Other names the type accepts, such as its CloudFormation type names.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"backend": schema.StringAttribute{
+							Description: "How the type is probed: native (built into the provider), declarative (from prober_definitions) or override (answered by overrides_strict).",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SupportedTypesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProbeProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProbeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	if len(providerData.UnknownConfig) > 0 {
+		d.unknownConfig = providerData.UnknownConfig
+		return
+	}
+
+	d.registry = providerData.Registry
+}
+
+func (d *SupportedTypesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// prober_definitions may be among the unknown arguments
+	if len(d.unknownConfig) > 0 {
+		resp.Diagnostics.Append(providerConfigUnknown(d.unknownConfig))
+		return
+	}
+
+	// Without provider configuration, list the built-in types.
+	registry := d.registry
+	if registry == nil {
+		registry = probe.NewProberRegistry(aws.Config{})
+	}
+
+	names := registry.SupportedTypes()
+	elements := make([]attr.Value, 0, len(names))
+	for _, name := range names {
+		aliases, diags := types.ListValueFrom(ctx, types.StringType, registry.Catalog().Aliases(name))
+		resp.Diagnostics.Append(diags...)
+
+		backend := BackendNative
+		if prober, err := registry.GetProber(name); err == nil {
+			backend = proberBackend(prober)
+		}
+
+		element, diags := types.ObjectValue(supportedTypeAttrTypes(), map[string]attr.Value{
+			"name":    types.StringValue(name),
+			"aliases": aliases,
+			"backend": types.StringValue(backend),
+		})
+		resp.Diagnostics.Append(diags...)
+		elements = append(elements, element)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data SupportedTypesDataSourceModel
+	var diags diag.Diagnostics
+	data.Names, diags = types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	data.Types, diags = types.ListValue(types.ObjectType{AttrTypes: supportedTypeAttrTypes()}, elements)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// proberBackend returns the backend prober uses.
+func proberBackend(prober probe.ResourceProber) string {
+	switch p := prober.(type) {
+	case *overrideProber:
+		if p.strict {
+			return BackendOverride
+		}
+		return proberBackend(p.wrapped())
+	case *DeclarativeProber:
+		return BackendDeclarative
+	default:
+		return BackendNative
+	}
+}
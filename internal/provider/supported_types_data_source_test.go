@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// supportedType is an element of the types attribute.
+type supportedType struct {
+	Name    string   `tfsdk:"name"`
+	Aliases []string `tfsdk:"aliases"`
+	Backend string   `tfsdk:"backend"`
+}
+
+// readSupportedTypes runs d.Read and returns its types.
+func readSupportedTypes(t *testing.T, d *SupportedTypesDataSource) map[string]supportedType {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, nil)},
+	}
+	d.Read(ctx, datasource.ReadRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(objType, nil)},
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data SupportedTypesDataSourceModel
+	var elements []supportedType
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	resp.Diagnostics.Append(data.Types.ElementsAs(ctx, &elements, false)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if len(data.Names.Elements()) != len(elements) {
+		t.Errorf("expected a name per type, got %v", data.Names)
+	}
+
+	byName := make(map[string]supportedType, len(elements))
+	for _, element := range elements {
+		byName[element.Name] = element
+	}
+	return byName
+}
+
+func TestSupportedTypesDataSource_Read(t *testing.T) {
+	cfg := aws.Config{Region: "us-east-1"}
+
+	t.Run("built-in types", func(t *testing.T) {
+		types := readSupportedTypes(t, &SupportedTypesDataSource{})
+		table, ok := types["aws_dynamodb_table"]
+		if !ok || table.Backend != BackendNative {
+			t.Fatalf("expected a native aws_dynamodb_table, got %+v", types)
+		}
+		want := []string{"AWS::DynamoDB::GlobalTable", "AWS::DynamoDB::Table", "dynamodb_table"}
+		if len(table.Aliases) != len(want) {
+			t.Fatalf("expected aliases %v, got %v", want, table.Aliases)
+		}
+		for i := range want {
+			if table.Aliases[i] != want[i] {
+				t.Errorf("expected aliases %v, got %v", want, table.Aliases)
+			}
+		}
+	})
+
+	t.Run("declarative and override backends", func(t *testing.T) {
+		catalog := probe.DefaultCatalog().Clone()
+		RegisterDefinitions(catalog, []ProberDefinition{testIamRoleDefinition()})
+
+		types := readSupportedTypes(t, &SupportedTypesDataSource{registry: probe.NewProberRegistryWithCatalog(cfg, catalog)})
+		if types["aws_iam_role"].Backend != BackendDeclarative {
+			t.Errorf("expected a declarative aws_iam_role, got %+v", types["aws_iam_role"])
+		}
+
+		overridden := ApplyOverrides(catalog, Overrides{"aws_s3_bucket": {"assets": {}}}, false)
+		types = readSupportedTypes(t, &SupportedTypesDataSource{registry: probe.NewProberRegistryWithCatalog(cfg, overridden)})
+		if types["aws_s3_bucket"].Backend != BackendNative || types["aws_iam_role"].Backend != BackendDeclarative {
+			t.Errorf("expected overrides to keep the wrapped backends, got %+v", types)
+		}
+
+		strict := ApplyOverrides(catalog, nil, true)
+		types = readSupportedTypes(t, &SupportedTypesDataSource{registry: probe.NewProberRegistryWithCatalog(cfg, strict)})
+		for name, typ := range types {
+			if typ.Backend != BackendOverride {
+				t.Errorf("expected %s to be answered by overrides, got %s", name, typ.Backend)
+			}
+		}
+	})
+}
+
+func TestAccSupportedTypesDataSource(t *testing.T) {
+	server := testAccFakeAWS(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+data "probe_supported_types" "all" {}
+
+output "s3_supported" {
+  value = contains(data.probe_supported_types.all.names, "aws_s3_bucket")
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("s3_supported", "true"),
+					resource.TestCheckResourceAttr("data.probe_supported_types.all", "types.0.name", "aws_dynamodb_table"),
+					resource.TestCheckResourceAttr("data.probe_supported_types.all", "types.0.backend", "native"),
+				),
+			},
+		},
+	})
+}
@@ -7,9 +7,11 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 
+	"github.com/shakefu/terraform-provider-probe/internal/cli"
 	"github.com/shakefu/terraform-provider-probe/internal/provider"
 )
 
@@ -18,6 +20,12 @@ import (
 var version string = "dev"
 
 func main() {
+	// Subcommands run the probe logic directly instead of serving the
+	// plugin protocol, e.g. `terraform-provider-probe check -type ... -id ...`.
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
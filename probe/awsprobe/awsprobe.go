@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package awsprobe provides the built-in AWS probers and registers them with
+// probe.DefaultCatalog. Tools that run probes without the provider import it
+// for its side effect:
+//
+//	import _ "github.com/shakefu/terraform-provider-probe/probe/awsprobe"
+//
+// It depends only on the probe package and the AWS SDK.
+package awsprobe
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+//go:generate go run ../../internal/cmd/gentypes -registry cloudformation_registry.json -package awsprobe -o cloudformation_types.go
+
+// Register the built-in probers with the default catalog. Aliases cover the
+// CloudFormation type names, from the generated cloudFormationTypes, and
+// short forms users commonly write.
+func init() {
+	probe.Register("aws_dynamodb_table", append(CloudFormationAliases("aws_dynamodb_table"),
+		"dynamodb_table", // short form
+	), func(cfg aws.Config) probe.ResourceProber {
+		return NewDynamoDBProber(cfg)
+	})
+
+	probe.Register("aws_s3_bucket", append(CloudFormationAliases("aws_s3_bucket"),
+		"s3_bucket",
+	), func(cfg aws.Config) probe.ResourceProber {
+		return NewS3Prober(cfg)
+	})
+}
+
+// CloudFormationAliases returns the CloudFormation type names of the
+// resources terraformType manages, sorted.
+func CloudFormationAliases(terraformType string) []string {
+	var aliases []string
+	for cfnType, name := range cloudFormationTypes {
+		if name == terraformType {
+			aliases = append(aliases, cfnType)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package awsprobe
+
+import (
+	"net/http"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/shakefu/terraform-provider-probe/internal/cassette"
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/internal/faults"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// getFakeAWSConfig starts a fake AWS server and returns an AWS config that
+// talks to it. Retries happen without backoff to keep tests fast.
+func getFakeAWSConfig(t *testing.T) (*fakeaws.Server, aws.Config) {
+	t.Helper()
+
+	server := fakeaws.New(t)
+	cfg := aws.Config{
+		Region:       fakeaws.Region,
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+		Retryer: func() aws.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) {
+				o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) {
+					return 0, nil
+				})
+			})
+		},
+	}
+
+	return server, cfg
+}
+
+// getFaultConfig is getFakeAWSConfig with requests sent through a fault
+// injecting transport.
+func getFaultConfig(t *testing.T) (*fakeaws.Server, *faults.Transport, aws.Config) {
+	t.Helper()
+
+	server, cfg := getFakeAWSConfig(t)
+	transport := faults.NewTransport(nil)
+	cfg.HTTPClient = transport.Client()
+
+	return server, transport, cfg
+}
+
+// getCassetteConfig returns an AWS config that answers requests from
+// testdata/cassettes/<name>.json. The test fails if any recorded interaction
+// goes unused.
+func getCassetteConfig(t *testing.T, name string) aws.Config {
+	t.Helper()
+
+	replayer, err := cassette.LoadReplayer(filepath.Join("testdata", "cassettes", name+".json"))
+	if err != nil {
+		t.Fatalf("failed to load cassette: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, unused := range replayer.Unused() {
+			t.Errorf("cassette %s: interaction not replayed: %s %s %s", name, unused.Request.Method, unused.Request.URL, unused.Request.Target)
+		}
+	})
+
+	return aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("test", "test", ""),
+		HTTPClient:  &http.Client{Transport: replayer},
+		Retryer: func() aws.Retryer {
+			return aws.NopRetryer{}
+		},
+	}
+}
+
+func TestDefaultCatalog(t *testing.T) {
+	catalog := probe.DefaultCatalog()
+	for _, typ := range []string{"aws_dynamodb_table", "aws_s3_bucket"} {
+		if _, ok := catalog.Factory(typ); !ok {
+			t.Errorf("expected %s to be registered", typ)
+		}
+	}
+	if got := catalog.Normalize("AWS::DynamoDB::Table"); got != "aws_dynamodb_table" {
+		t.Errorf("Normalize(AWS::DynamoDB::Table) = %q, want aws_dynamodb_table", got)
+	}
+	if got := CloudFormationAliases("aws_dynamodb_table"); !slices.Equal(got, []string{"AWS::DynamoDB::GlobalTable", "AWS::DynamoDB::Table"}) {
+		t.Errorf("CloudFormationAliases(aws_dynamodb_table) = %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package awsprobe
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// dynamoDBTableName matches the names DynamoDB allows for tables.
+var dynamoDBTableName = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,255}$`)
+
+// dynamoDBTableArn matches table ARNs, which DescribeTable accepts in place
+// of a name.
+var dynamoDBTableArn = regexp.MustCompile(`^arn:aws[a-z-]*:dynamodb:[a-z0-9-]+:[0-9]{12}:table/[a-zA-Z0-9_.-]{3,255}$`)
+
+// DynamoDBProber probes DynamoDB tables using the native AWS SDK.
+type DynamoDBProber struct {
+	client *dynamodb.Client
+}
+
+// NewDynamoDBProber creates a new DynamoDB prober from an AWS config.
+func NewDynamoDBProber(cfg aws.Config) *DynamoDBProber {
+	return &DynamoDBProber{
+		client: dynamodb.NewFromConfig(cfg),
+	}
+}
+
+// Service implements probe.ServiceProber.
+func (p *DynamoDBProber) Service() string {
+	return "dynamodb"
+}
+
+// ImmutableProperties implements probe.ImmutableProber. Changing either
+// forces aws_dynamodb_table to replace the table.
+func (p *DynamoDBProber) ImmutableProperties() []probe.ImmutableProperty {
+	return []probe.ImmutableProperty{
+		{Name: "KeySchema"},
+		{Name: "AttributeDefinitions", Unordered: true},
+	}
+}
+
+// ValidateIdentifier implements probe.IdentifierValidator.
+func (p *DynamoDBProber) ValidateIdentifier(identifier string) error {
+	switch {
+	case strings.HasPrefix(identifier, "arn:"):
+		if !dynamoDBTableArn.MatchString(identifier) {
+			return errors.New("table ARNs have the form arn:aws:dynamodb:REGION:ACCOUNT:table/NAME")
+		}
+	case len(identifier) < 3 || len(identifier) > 255:
+		return errors.New("table names are 3 to 255 characters long")
+	case !dynamoDBTableName.MatchString(identifier):
+		return errors.New("table names may only contain letters, digits, underscores (_), hyphens (-) and periods (.)")
+	}
+	return nil
+}
+
+// dynamoDBLifecycle maps a table status to a lifecycle state. A table whose
+// KMS key is inaccessible is still active until DynamoDB archives it.
+func dynamoDBLifecycle(status types.TableStatus) string {
+	switch status {
+	case types.TableStatusCreating:
+		return probe.LifecycleCreating
+	case types.TableStatusUpdating:
+		return probe.LifecycleUpdating
+	case types.TableStatusDeleting:
+		return probe.LifecycleDeleting
+	case types.TableStatusArchiving, types.TableStatusArchived:
+		return probe.LifecycleArchived
+	default:
+		return probe.LifecycleActive
+	}
+}
+
+// Probe checks whether a DynamoDB table exists and retrieves its properties.
+// The identifier is the table name or ARN.
+func (p *DynamoDBProber) Probe(ctx context.Context, identifier string) (*probe.ProbeResult, error) {
+	// DescribeTable returns the table description or ResourceNotFoundException
+	output, err := p.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(identifier),
+	})
+
+	if err != nil {
+		// Check if the table does not exist
+		var notFoundErr *types.ResourceNotFoundException
+		if errors.As(err, &notFoundErr) {
+			return &probe.ProbeResult{Exists: false}, nil
+		}
+		// Other errors are unexpected
+		return nil, err
+	}
+
+	table := output.Table
+	result := &probe.ProbeResult{
+		Exists:         true,
+		Arn:            aws.ToString(table.TableArn),
+		TerraformType:  "aws_dynamodb_table",
+		ImportID:       aws.ToString(table.TableName),
+		Status:         string(table.TableStatus),
+		LifecycleState: dynamoDBLifecycle(table.TableStatus),
+		Properties: map[string]any{
+			"TableName":             aws.ToString(table.TableName),
+			"TableArn":              aws.ToString(table.TableArn),
+			"TableStatus":           string(table.TableStatus),
+			"TableId":               aws.ToString(table.TableId),
+			"CreationDateTime":      table.CreationDateTime,
+			"ItemCount":             table.ItemCount,
+			"TableSizeBytes":        table.TableSizeBytes,
+			"DeletionProtection":    aws.ToBool(table.DeletionProtectionEnabled),
+			"BillingModeSummary":    table.BillingModeSummary,
+			"ProvisionedThroughput": table.ProvisionedThroughput,
+			"KeySchema":             table.KeySchema,
+			"AttributeDefinitions":  table.AttributeDefinitions,
+		},
+	}
+
+	// Get tags using the ARN
+	if result.Arn != "" {
+		tags, err := p.client.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{
+			ResourceArn: aws.String(result.Arn),
+		})
+		if err == nil && len(tags.Tags) > 0 {
+			result.Tags = make(map[string]string, len(tags.Tags))
+			for _, tag := range tags.Tags {
+				result.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			// Also add tags to properties for visibility
+			result.Properties["Tags"] = result.Tags
+		}
+	}
+
+	return result, nil
+}
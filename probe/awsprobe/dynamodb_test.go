@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package awsprobe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/internal/faults"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+func TestDynamoDBProber_ValidateIdentifier(t *testing.T) {
+	p := NewDynamoDBProber(aws.Config{Region: "us-east-1"})
+
+	tests := []struct {
+		identifier string
+		wantErr    string
+	}{
+		{identifier: "orders"},
+		{identifier: "Orders_v2.archive-1"},
+		{identifier: "arn:aws:dynamodb:us-east-1:123456789012:table/orders"},
+		{identifier: "arn:aws-cn:dynamodb:cn-north-1:123456789012:table/orders"},
+		{identifier: "ab", wantErr: "3 to 255 characters"},
+		{identifier: "orders table", wantErr: "may only contain"},
+		{identifier: "arn:aws:dynamodb:us-east-1:123456789012:orders", wantErr: "table/NAME"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			err := p.ValidateIdentifier(tt.identifier)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDynamoDBLifecycle(t *testing.T) {
+	tests := map[types.TableStatus]string{
+		types.TableStatusActive:                            probe.LifecycleActive,
+		types.TableStatusInaccessibleEncryptionCredentials: probe.LifecycleActive,
+		types.TableStatusCreating:                          probe.LifecycleCreating,
+		types.TableStatusUpdating:                          probe.LifecycleUpdating,
+		types.TableStatusDeleting:                          probe.LifecycleDeleting,
+		types.TableStatusArchiving:                         probe.LifecycleArchived,
+		types.TableStatusArchived:                          probe.LifecycleArchived,
+	}
+	for status, expected := range tests {
+		if got := dynamoDBLifecycle(status); got != expected {
+			t.Errorf("dynamoDBLifecycle(%s) = %q, want %q", status, got, expected)
+		}
+	}
+}
+
+func TestDynamoDBProber_Cassette(t *testing.T) {
+	cfg := getCassetteConfig(t, "dynamodb_table")
+	prober := NewDynamoDBProber(cfg)
+	ctx := context.Background()
+
+	result, err := prober.Probe(ctx, "orders")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Exists || result.Arn != "arn:aws:dynamodb:us-east-1:123456789012:table/orders" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Properties["DeletionProtection"] != true {
+		t.Errorf("expected DeletionProtection=true, got %v", result.Properties["DeletionProtection"])
+	}
+	if size, ok := result.Properties["TableSizeBytes"].(*int64); !ok || aws.ToInt64(size) != 241920 {
+		t.Errorf("expected TableSizeBytes=241920, got %v", result.Properties["TableSizeBytes"])
+	}
+	if result.Tags["Team"] != "checkout" {
+		t.Errorf("expected Team tag, got %v", result.Tags)
+	}
+
+	missing, err := prober.Probe(ctx, "missing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if missing.Exists {
+		t.Error("expected Exists to be false for missing table")
+	}
+}
+
+func TestDynamoDBProber_Fake(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutTable(fakeaws.Table{
+		Name:               "orders",
+		DeletionProtection: true,
+		Tags:               map[string]string{"Environment": "test"},
+	})
+	ctx := context.Background()
+
+	t.Run("table not found", func(t *testing.T) {
+		result, err := NewDynamoDBProber(cfg).Probe(ctx, "missing")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Exists {
+			t.Error("expected Exists to be false for nonexistent table")
+		}
+	})
+
+	t.Run("table exists", func(t *testing.T) {
+		result, err := NewDynamoDBProber(cfg).Probe(ctx, "orders")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists {
+			t.Fatal("expected Exists to be true")
+		}
+		if result.Arn != "arn:aws:dynamodb:us-east-1:123456789012:table/orders" {
+			t.Errorf("unexpected ARN %q", result.Arn)
+		}
+		if result.Properties["TableStatus"] != "ACTIVE" {
+			t.Errorf("expected TableStatus=ACTIVE, got %v", result.Properties["TableStatus"])
+		}
+		if result.Status != "ACTIVE" {
+			t.Errorf("expected Status=ACTIVE, got %q", result.Status)
+		}
+		if result.Properties["DeletionProtection"] != true {
+			t.Errorf("expected DeletionProtection=true, got %v", result.Properties["DeletionProtection"])
+		}
+		if result.Tags["Environment"] != "test" {
+			t.Errorf("expected Environment tag, got %v", result.Tags)
+		}
+	})
+
+	t.Run("tag errors are ignored", func(t *testing.T) {
+		server.Fail(fakeaws.OpListTagsOfResource, fakeaws.Error{Status: http.StatusBadRequest, Code: "AccessDeniedException", Message: "denied"})
+		t.Cleanup(server.ClearFaults)
+
+		result, err := NewDynamoDBProber(cfg).Probe(ctx, "orders")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists || result.Tags != nil {
+			t.Errorf("expected table without tags, got exists=%v tags=%v", result.Exists, result.Tags)
+		}
+	})
+
+	t.Run("describe errors are returned", func(t *testing.T) {
+		server.Fail(fakeaws.OpDescribeTable, fakeaws.Error{Status: http.StatusBadRequest, Code: "AccessDeniedException", Message: "denied"})
+		t.Cleanup(server.ClearFaults)
+
+		if _, err := NewDynamoDBProber(cfg).Probe(ctx, "orders"); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("throttling is retried", func(t *testing.T) {
+		server.FailN(fakeaws.OpDescribeTable, 2, fakeaws.Error{Status: http.StatusBadRequest, Code: "ThrottlingException", Message: "slow down"})
+		t.Cleanup(server.ClearFaults)
+
+		before := server.CallCount(fakeaws.OpDescribeTable)
+		result, err := NewDynamoDBProber(cfg).Probe(ctx, "orders")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists {
+			t.Error("expected Exists to be true after retries")
+		}
+		if calls := server.CallCount(fakeaws.OpDescribeTable) - before; calls != 3 {
+			t.Errorf("expected 3 DescribeTable calls, got %d", calls)
+		}
+	})
+}
+
+func TestDynamoDBProber_Faults(t *testing.T) {
+	server, transport, cfg := getFaultConfig(t)
+	server.PutTable(fakeaws.Table{Name: "orders", Tags: map[string]string{"Environment": "test"}})
+	ctx := context.Background()
+
+	t.Run("transient faults are retried", func(t *testing.T) {
+		for name, fault := range map[string]faults.Fault{
+			"unavailable":      faults.Unavailable(),
+			"throttled":        faults.Throttled(),
+			"connection reset": faults.ConnectionReset(),
+		} {
+			t.Run(name, func(t *testing.T) {
+				transport.Reset()
+				transport.InjectN("dynamodb", fakeaws.OpDescribeTable, 1, fault)
+
+				result, err := NewDynamoDBProber(cfg).Probe(ctx, "orders")
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !result.Exists {
+					t.Error("expected Exists to be true after retry")
+				}
+				if calls := transport.Calls(fakeaws.OpDescribeTable); calls != 2 {
+					t.Errorf("expected 2 DescribeTable calls, got %d", calls)
+				}
+			})
+		}
+	})
+
+	t.Run("persistent throttling gives up", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("dynamodb", fakeaws.OpDescribeTable, faults.Throttled())
+
+		_, err := NewDynamoDBProber(cfg).Probe(ctx, "orders")
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ThrottlingException" {
+			t.Fatalf("expected ThrottlingException, got %v", err)
+		}
+		if calls := transport.Calls(fakeaws.OpDescribeTable); calls != 3 {
+			t.Errorf("expected 3 DescribeTable calls, got %d", calls)
+		}
+	})
+
+	t.Run("expired credentials are not retried", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("dynamodb", "", faults.ExpiredCredentials())
+
+		_, err := NewDynamoDBProber(cfg).Probe(ctx, "orders")
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ExpiredTokenException" {
+			t.Fatalf("expected ExpiredTokenException, got %v", err)
+		}
+		if calls := transport.Calls(fakeaws.OpDescribeTable); calls != 1 {
+			t.Errorf("expected 1 DescribeTable call, got %d", calls)
+		}
+	})
+
+	t.Run("malformed body is an error", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("dynamodb", fakeaws.OpDescribeTable, faults.MalformedBody())
+
+		if _, err := NewDynamoDBProber(cfg).Probe(ctx, "orders"); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("latency past the deadline is an error", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("dynamodb", fakeaws.OpDescribeTable, faults.Latency(time.Minute))
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err := NewDynamoDBProber(cfg).Probe(ctx, "orders")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("tag faults drop tags", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("dynamodb", fakeaws.OpListTagsOfResource, faults.ExpiredCredentials())
+
+		result, err := NewDynamoDBProber(cfg).Probe(ctx, "orders")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists || result.Tags != nil {
+			t.Errorf("expected table without tags, got exists=%v tags=%v", result.Exists, result.Tags)
+		}
+	})
+}
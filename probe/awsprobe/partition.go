@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package awsprobe
+
+import "strings"
+
+// regionPartitions maps region prefixes to partitions other than aws, and
+// their DNS suffixes, most specific first.
+var regionPartitions = []struct {
+	prefix    string
+	partition string
+	dnsSuffix string
+}{
+	{"cn-", "aws-cn", "amazonaws.com.cn"},
+	{"us-gov-", "aws-us-gov", "amazonaws.com"},
+	{"us-isob-", "aws-iso-b", "sc2s.sgov.gov"},
+	{"us-isof-", "aws-iso-f", "csp.hci.ic.gov"},
+	{"us-iso-", "aws-iso", "c2s.ic.gov"},
+	{"eu-isoe-", "aws-iso-e", "cloud.adc-e.uk"},
+}
+
+// Partition returns the partition region belongs to, for building ARNs.
+// Unrecognized regions, including an empty one, are in the aws partition.
+func Partition(region string) string {
+	for _, p := range regionPartitions {
+		if strings.HasPrefix(region, p.prefix) {
+			return p.partition
+		}
+	}
+	return "aws"
+}
+
+// DNSSuffix returns the DNS suffix of service endpoints in region's
+// partition.
+func DNSSuffix(region string) string {
+	for _, p := range regionPartitions {
+		if strings.HasPrefix(region, p.prefix) {
+			return p.dnsSuffix
+		}
+	}
+	return "amazonaws.com"
+}
@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package awsprobe
+
+import "testing"
+
+func TestPartition(t *testing.T) {
+	tests := map[string]string{
+		"us-east-1":       "aws",
+		"eu-west-1":       "aws",
+		"":                "aws",
+		"cn-north-1":      "aws-cn",
+		"cn-northwest-1":  "aws-cn",
+		"us-gov-west-1":   "aws-us-gov",
+		"us-iso-east-1":   "aws-iso",
+		"us-isob-east-1":  "aws-iso-b",
+		"eu-isoe-west-1":  "aws-iso-e",
+		"us-isof-south-1": "aws-iso-f",
+	}
+	for region, expected := range tests {
+		if got := Partition(region); got != expected {
+			t.Errorf("Partition(%q) = %q, want %q", region, got, expected)
+		}
+	}
+}
+
+func TestDNSSuffix(t *testing.T) {
+	tests := map[string]string{
+		"us-east-1":      "amazonaws.com",
+		"cn-north-1":     "amazonaws.com.cn",
+		"us-gov-west-1":  "amazonaws.com",
+		"us-iso-east-1":  "c2s.ic.gov",
+		"us-isob-east-1": "sc2s.sgov.gov",
+	}
+	for region, expected := range tests {
+		if got := DNSSuffix(region); got != expected {
+			t.Errorf("DNSSuffix(%q) = %q, want %q", region, got, expected)
+		}
+	}
+}
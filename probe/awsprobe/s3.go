@@ -0,0 +1,227 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package awsprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// s3BucketArn matches bucket ARNs, capturing the bucket name.
+var s3BucketArn = regexp.MustCompile(`^arn:aws[a-z-]*:s3:::([^/]+)$`)
+
+// s3BucketChars matches the characters any bucket name, including a legacy
+// one, is made of.
+var s3BucketChars = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// s3ReservedPrefixes and s3ReservedSuffixes can't begin or end the names of
+// new general purpose buckets.
+var (
+	s3ReservedPrefixes = []string{"xn--", "sthree-", "amzn-s3-demo-"}
+	s3ReservedSuffixes = []string{".mrap", "--x-s3", "--table-s3"}
+)
+
+// s3AliasSuffixes end access point aliases, which HeadBucket accepts in
+// place of a bucket name.
+var s3AliasSuffixes = []string{"-s3alias", "--ol-s3"}
+
+// S3Prober probes S3 buckets using the native AWS SDK.
+type S3Prober struct {
+	client *s3.Client
+	region string
+}
+
+// NewS3Prober creates a new S3 prober from an AWS config.
+func NewS3Prober(cfg aws.Config) *S3Prober {
+	// Check if we're using a custom endpoint (e.g., LocalStack)
+	usePathStyle := cfg.BaseEndpoint != nil && *cfg.BaseEndpoint != ""
+
+	return &S3Prober{
+		client: s3.NewFromConfig(cfg, func(o *s3.Options) {
+			if usePathStyle {
+				o.UsePathStyle = true
+			}
+			// A throttled HeadBucket has no body, so the SDK only sees the
+			// generic TooManyRequests code, which it doesn't retry by default.
+			if o.Retryer != nil {
+				o.Retryer = retry.AddWithErrorCodes(o.Retryer, "TooManyRequests")
+			}
+		}),
+		region: cfg.Region,
+	}
+}
+
+// Service implements probe.ServiceProber.
+func (p *S3Prober) Service() string {
+	return "s3"
+}
+
+// ImmutableProperties implements probe.ImmutableProber. A bucket can't move
+// to another region.
+func (p *S3Prober) ImmutableProperties() []probe.ImmutableProperty {
+	return []probe.ImmutableProperty{{Name: "Region"}}
+}
+
+// ValidateIdentifier implements probe.IdentifierValidator. It only rejects
+// identifiers no bucket can have; legacy us-east-1 buckets may break the
+// current naming rules, which LintIdentifier checks.
+func (p *S3Prober) ValidateIdentifier(identifier string) error {
+	if match := s3BucketArn.FindStringSubmatch(identifier); match != nil {
+		return fmt.Errorf("use the bucket name %q instead of its ARN", match[1])
+	}
+	if strings.HasPrefix(identifier, "arn:") {
+		return errors.New("S3 probes take a bucket name, not an ARN")
+	}
+
+	if identifier == "" {
+		return errors.New("bucket names must not be empty")
+	}
+	if len(identifier) > 255 {
+		return errors.New("bucket names are at most 255 characters long")
+	}
+	if !s3BucketChars.MatchString(identifier) {
+		return errors.New("bucket names may only contain letters, digits, periods (.), underscores (_) and hyphens (-)")
+	}
+	return nil
+}
+
+// LintIdentifier implements probe.IdentifierLinter with the S3 naming rules
+// for new general purpose buckets. Access point aliases are accepted.
+func (p *S3Prober) LintIdentifier(identifier string) error {
+	for _, suffix := range s3AliasSuffixes {
+		if strings.HasSuffix(identifier, suffix) {
+			return nil
+		}
+	}
+
+	if len(identifier) < 3 || len(identifier) > 63 {
+		return errors.New("bucket names are 3 to 63 characters long")
+	}
+	for _, r := range identifier {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '.' && r != '-' {
+			return errors.New("bucket names may only contain lowercase letters, digits, periods (.) and hyphens (-)")
+		}
+	}
+	if first, last := identifier[0], identifier[len(identifier)-1]; !isAlphanumeric(first) || !isAlphanumeric(last) {
+		return errors.New("bucket names must begin and end with a letter or digit")
+	}
+	if strings.Contains(identifier, "..") {
+		return errors.New("bucket names must not contain two adjacent periods")
+	}
+	if ip := net.ParseIP(identifier); ip != nil {
+		return errors.New("bucket names must not be formatted as an IP address")
+	}
+	for _, prefix := range s3ReservedPrefixes {
+		if strings.HasPrefix(identifier, prefix) {
+			return fmt.Errorf("bucket names must not begin with %q", prefix)
+		}
+	}
+	for _, suffix := range s3ReservedSuffixes {
+		if strings.HasSuffix(identifier, suffix) {
+			return fmt.Errorf("bucket names must not end with %q", suffix)
+		}
+	}
+	return nil
+}
+
+// isAlphanumeric reports whether b is a lowercase letter or digit.
+func isAlphanumeric(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9')
+}
+
+// Probe checks whether an S3 bucket exists and retrieves its properties.
+// The identifier is the bucket name.
+func (p *S3Prober) Probe(ctx context.Context, identifier string) (*probe.ProbeResult, error) {
+	// HeadBucket returns success or NotFound/Forbidden
+	_, err := p.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(identifier),
+	})
+
+	if err != nil {
+		// Check if the bucket does not exist
+		var notFoundErr *types.NotFound
+		var noSuchBucket *types.NoSuchBucket
+		if errors.As(err, &notFoundErr) || errors.As(err, &noSuchBucket) {
+			return &probe.ProbeResult{Exists: false}, nil
+		}
+		// Other errors (like 403 Forbidden for buckets you don't own) should
+		// also be treated as "not found" since you can't access them
+		// Check for S3-style "Not Found" responses
+		if isS3NotFound(err) {
+			return &probe.ProbeResult{Exists: false}, nil
+		}
+		// Other errors are unexpected
+		return nil, err
+	}
+
+	// Bucket exists - construct ARN
+	bucketArn := arn.ARN{Partition: Partition(p.region), Service: "s3", Resource: identifier}.String()
+
+	result := &probe.ProbeResult{
+		Exists:         true,
+		Arn:            bucketArn,
+		LifecycleState: probe.LifecycleActive, // buckets have no status
+		TerraformType:  "aws_s3_bucket",
+		ImportID:       identifier,
+		Properties: map[string]any{
+			"BucketName": identifier,
+			"Arn":        bucketArn,
+		},
+	}
+
+	// Get bucket location (region)
+	location, err := p.client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket: aws.String(identifier),
+	})
+	if err == nil {
+		region := string(location.LocationConstraint)
+		if region == "" {
+			region = "us-east-1" // Default region has empty LocationConstraint
+		}
+		result.Properties["Region"] = region
+	}
+
+	// Get tags
+	tags, err := p.client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: aws.String(identifier),
+	})
+	if err == nil && len(tags.TagSet) > 0 {
+		result.Tags = make(map[string]string, len(tags.TagSet))
+		for _, tag := range tags.TagSet {
+			result.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		result.Properties["Tags"] = result.Tags
+	}
+
+	return result, nil
+}
+
+// isS3NotFound checks for various S3 "not found" error responses.
+func isS3NotFound(err error) bool {
+	// S3 returns 404 as a generic HTTP error in some cases
+	errStr := err.Error()
+	return contains(errStr, "404") || contains(errStr, "NotFound") || contains(errStr, "NoSuchBucket")
+}
+
+// contains is a simple string contains helper.
+func contains(s, substr string) bool {
+	for i := 0; i <= len(s)-len(substr); i++ {
+		if s[i:i+len(substr)] == substr {
+			return true
+		}
+	}
+	return false
+}
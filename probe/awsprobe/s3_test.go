@@ -0,0 +1,396 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package awsprobe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/shakefu/terraform-provider-probe/internal/fakeaws"
+	"github.com/shakefu/terraform-provider-probe/internal/faults"
+)
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		substr   string
+		expected bool
+	}{
+		{
+			name:     "substring at start",
+			s:        "hello world",
+			substr:   "hello",
+			expected: true,
+		},
+		{
+			name:     "substring at end",
+			s:        "hello world",
+			substr:   "world",
+			expected: true,
+		},
+		{
+			name:     "substring in middle",
+			s:        "hello world",
+			substr:   "lo wo",
+			expected: true,
+		},
+		{
+			name:     "exact match",
+			s:        "hello",
+			substr:   "hello",
+			expected: true,
+		},
+		{
+			name:     "substring not found",
+			s:        "hello world",
+			substr:   "xyz",
+			expected: false,
+		},
+		{
+			name:     "empty substring",
+			s:        "hello",
+			substr:   "",
+			expected: true,
+		},
+		{
+			name:     "empty string",
+			s:        "",
+			substr:   "hello",
+			expected: false,
+		},
+		{
+			name:     "both empty",
+			s:        "",
+			substr:   "",
+			expected: true,
+		},
+		{
+			name:     "substring longer than string",
+			s:        "hi",
+			substr:   "hello",
+			expected: false,
+		},
+		{
+			name:     "case sensitive",
+			s:        "Hello World",
+			substr:   "hello",
+			expected: false,
+		},
+		{
+			name:     "numeric substring",
+			s:        "error 404 not found",
+			substr:   "404",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := contains(tt.s, tt.substr)
+			if result != tt.expected {
+				t.Errorf("contains(%q, %q) = %v, want %v", tt.s, tt.substr, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsS3NotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "error contains 404",
+			err:      errors.New("api error: 404 bucket not found"),
+			expected: true,
+		},
+		{
+			name:     "error contains NotFound",
+			err:      errors.New("operation error: NotFound"),
+			expected: true,
+		},
+		{
+			name:     "error contains NoSuchBucket",
+			err:      errors.New("NoSuchBucket: The specified bucket does not exist"),
+			expected: true,
+		},
+		{
+			name:     "generic error",
+			err:      errors.New("access denied"),
+			expected: false,
+		},
+		{
+			name:     "timeout error",
+			err:      errors.New("connection timeout"),
+			expected: false,
+		},
+		{
+			name:     "403 forbidden",
+			err:      errors.New("403 Forbidden"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := isS3NotFound(tt.err)
+			if result != tt.expected {
+				t.Errorf("isS3NotFound(%v) = %v, want %v", tt.err, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestS3Prober_ValidateIdentifier(t *testing.T) {
+	p := NewS3Prober(aws.Config{Region: "us-east-1"})
+
+	tests := []struct {
+		identifier string
+		wantErr    string
+		wantLint   string
+	}{
+		{identifier: "my-bucket"},
+		{identifier: "logs.example.com"},
+		{identifier: "abc"},
+		{identifier: "my-ap-abcdefghijklmnopqrstuvwxyz0123-s3alias"},
+		{identifier: "ab", wantLint: "3 to 63 characters"},
+		{identifier: strings.Repeat("a", 64), wantLint: "3 to 63 characters"},
+		{identifier: "My_Bucket", wantLint: "lowercase letters, digits"},
+		{identifier: "-bucket", wantLint: "begin and end with a letter or digit"},
+		{identifier: "bucket.", wantLint: "begin and end with a letter or digit"},
+		{identifier: "my..bucket", wantLint: "two adjacent periods"},
+		{identifier: "192.168.5.4", wantLint: "IP address"},
+		{identifier: "xn--bucket", wantLint: `begin with "xn--"`},
+		{identifier: "bucket--x-s3", wantLint: `end with "--x-s3"`},
+		{identifier: "", wantErr: "must not be empty"},
+		{identifier: strings.Repeat("a", 256), wantErr: "at most 255 characters"},
+		{identifier: "assets/logs", wantErr: "letters, digits, periods"},
+		{identifier: "arn:aws:s3:::my-bucket", wantErr: `use the bucket name "my-bucket"`},
+		{identifier: "arn:aws:s3:us-east-1:123456789012:accesspoint/ap", wantErr: "not an ARN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			err := p.ValidateIdentifier(tt.identifier)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			lint := p.LintIdentifier(tt.identifier)
+			switch {
+			case tt.wantLint == "" && lint != nil:
+				t.Errorf("unexpected lint: %v", lint)
+			case tt.wantLint != "" && (lint == nil || !strings.Contains(lint.Error(), tt.wantLint)):
+				t.Errorf("expected lint containing %q, got %v", tt.wantLint, lint)
+			}
+		})
+	}
+}
+
+func TestS3Prober_Cassette(t *testing.T) {
+	cfg := getCassetteConfig(t, "s3_bucket")
+	prober := NewS3Prober(cfg)
+	ctx := context.Background()
+
+	result, err := prober.Probe(ctx, "assets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Exists || result.Arn != "arn:aws:s3:::assets" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Properties["Region"] != "eu-west-1" {
+		t.Errorf("expected Region=eu-west-1, got %v", result.Properties["Region"])
+	}
+	if result.Tags["aws:cloudformation:stack-name"] != "assets" {
+		t.Errorf("expected CloudFormation tag, got %v", result.Tags)
+	}
+
+	missing, err := prober.Probe(ctx, "missing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if missing.Exists {
+		t.Error("expected Exists to be false for missing bucket")
+	}
+}
+
+func TestS3Prober_Fake(t *testing.T) {
+	server, cfg := getFakeAWSConfig(t)
+	server.PutBucket("assets", fakeaws.Bucket{
+		Region: "eu-west-1",
+		Tags:   map[string]string{"Environment": "test", "Owner": "probe-provider"},
+	})
+	server.PutBucket("untagged", fakeaws.Bucket{})
+	ctx := context.Background()
+
+	t.Run("bucket not found", func(t *testing.T) {
+		result, err := NewS3Prober(cfg).Probe(ctx, "missing")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Exists {
+			t.Error("expected Exists to be false for nonexistent bucket")
+		}
+	})
+
+	t.Run("bucket exists", func(t *testing.T) {
+		result, err := NewS3Prober(cfg).Probe(ctx, "assets")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists {
+			t.Fatal("expected Exists to be true")
+		}
+		if result.Arn != "arn:aws:s3:::assets" {
+			t.Errorf("unexpected ARN %q", result.Arn)
+		}
+		if result.Properties["Region"] != "eu-west-1" {
+			t.Errorf("expected Region=eu-west-1, got %v", result.Properties["Region"])
+		}
+		if result.Tags["Owner"] != "probe-provider" {
+			t.Errorf("expected Owner tag, got %v", result.Tags)
+		}
+	})
+
+	t.Run("bucket without tags in us-east-1", func(t *testing.T) {
+		result, err := NewS3Prober(cfg).Probe(ctx, "untagged")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Properties["Region"] != "us-east-1" {
+			t.Errorf("expected Region=us-east-1, got %v", result.Properties["Region"])
+		}
+		if result.Tags != nil {
+			t.Errorf("expected no tags, got %v", result.Tags)
+		}
+	})
+
+	t.Run("ARN in the config's partition", func(t *testing.T) {
+		cn := cfg.Copy()
+		cn.Region = "cn-north-1"
+		result, err := NewS3Prober(cn).Probe(ctx, "assets")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Arn != "arn:aws-cn:s3:::assets" || result.Properties["Arn"] != result.Arn {
+			t.Errorf("unexpected ARN %q", result.Arn)
+		}
+	})
+
+	t.Run("forbidden is an error", func(t *testing.T) {
+		server.Fail(fakeaws.OpHeadBucket, fakeaws.Error{Status: http.StatusForbidden, Code: "AccessDenied", Message: "Access Denied"})
+		t.Cleanup(server.ClearFaults)
+
+		if _, err := NewS3Prober(cfg).Probe(ctx, "assets"); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestS3Prober_Faults(t *testing.T) {
+	server, transport, cfg := getFaultConfig(t)
+	server.PutBucket("assets", fakeaws.Bucket{
+		Region: "eu-west-1",
+		Tags:   map[string]string{"Environment": "test"},
+	})
+	ctx := context.Background()
+
+	t.Run("transient faults are retried", func(t *testing.T) {
+		for name, fault := range map[string]faults.Fault{
+			"unavailable":      faults.Unavailable(),
+			"throttled":        faults.Throttled(),
+			"connection reset": faults.ConnectionReset(),
+		} {
+			t.Run(name, func(t *testing.T) {
+				transport.Reset()
+				transport.InjectN("s3", fakeaws.OpHeadBucket, 1, fault)
+
+				result, err := NewS3Prober(cfg).Probe(ctx, "assets")
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !result.Exists {
+					t.Error("expected Exists to be true after retry")
+				}
+				if calls := transport.Calls(fakeaws.OpHeadBucket); calls != 2 {
+					t.Errorf("expected 2 HeadBucket calls, got %d", calls)
+				}
+			})
+		}
+	})
+
+	t.Run("persistent unavailability gives up", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("s3", fakeaws.OpHeadBucket, faults.Unavailable())
+
+		result, err := NewS3Prober(cfg).Probe(ctx, "assets")
+		if err == nil {
+			t.Fatalf("expected error, got exists=%v", result.Exists)
+		}
+		if calls := transport.Calls(fakeaws.OpHeadBucket); calls != 3 {
+			t.Errorf("expected 3 HeadBucket calls, got %d", calls)
+		}
+	})
+
+	t.Run("expired credentials are not mistaken for a missing bucket", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("s3", "", faults.ExpiredCredentials())
+
+		var respErr interface{ HTTPStatusCode() int }
+		_, err := NewS3Prober(cfg).Probe(ctx, "assets")
+		if !errors.As(err, &respErr) || respErr.HTTPStatusCode() != http.StatusForbidden {
+			t.Fatalf("expected 403 error, got %v", err)
+		}
+		if calls := transport.Calls(fakeaws.OpHeadBucket); calls != 1 {
+			t.Errorf("expected 1 HeadBucket call, got %d", calls)
+		}
+	})
+
+	t.Run("latency past the deadline is an error", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("s3", fakeaws.OpHeadBucket, faults.Latency(time.Minute))
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err := NewS3Prober(cfg).Probe(ctx, "assets")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("location and tag faults are ignored", func(t *testing.T) {
+		transport.Reset()
+		transport.Inject("s3", fakeaws.OpGetBucketLocation, faults.MalformedBody())
+		transport.Inject("s3", fakeaws.OpGetBucketTagging, faults.ExpiredCredentials())
+
+		result, err := NewS3Prober(cfg).Probe(ctx, "assets")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists {
+			t.Fatal("expected Exists to be true")
+		}
+		if _, ok := result.Properties["Region"]; ok {
+			t.Errorf("expected no Region from a malformed response, got %v", result.Properties["Region"])
+		}
+		if result.Tags != nil {
+			t.Errorf("expected no tags, got %v", result.Tags)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package builder constructs probe provider instances with additional
+// probers, for distributing a provider binary that supports custom resource
+// types alongside the built-in ones.
+//
+//	func main() {
+//		factory := builder.New(version).
+//			WithProber("aws_example_widget", []string{"AWS::Example::Widget"}, newWidgetProber).
+//			Build()
+//		providerserver.Serve(context.Background(), factory, opts)
+//	}
+package builder
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+
+	internal "github.com/shakefu/terraform-provider-probe/internal/provider"
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// registration is a prober added with WithProber.
+type registration struct {
+	canonicalType string
+	aliases       []string
+	factory       probe.ProberFactory
+}
+
+// Builder configures a provider instance.
+type Builder struct {
+	version string
+	probers []registration
+}
+
+// New creates a builder for a provider reporting the given version.
+func New(version string) *Builder {
+	return &Builder{version: version}
+}
+
+// WithProber adds a resource type to the provider. It replaces a built-in
+// prober registered under the same canonical type.
+func (b *Builder) WithProber(canonicalType string, aliases []string, factory probe.ProberFactory) *Builder {
+	b.probers = append(b.probers, registration{
+		canonicalType: canonicalType,
+		aliases:       aliases,
+		factory:       factory,
+	})
+	return b
+}
+
+// Build returns the provider factory. The provider supports the types in the
+// default catalog at the time Build is called plus those added with
+// WithProber; the default catalog itself is not modified.
+func (b *Builder) Build() func() provider.Provider {
+	return internal.NewWithCatalog(b.version, b.catalog())
+}
+
+// catalog returns a copy of the default catalog extended with the builder's
+// probers.
+func (b *Builder) catalog() *probe.Catalog {
+	catalog := probe.DefaultCatalog().Clone()
+	for _, r := range b.probers {
+		catalog.Register(r.canonicalType, r.aliases, r.factory)
+	}
+	return catalog
+}
@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package builder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+type widgetProber struct{}
+
+func (widgetProber) Probe(_ context.Context, identifier string) (*probe.ProbeResult, error) {
+	return &probe.ProbeResult{Exists: true, ImportID: identifier}, nil
+}
+
+func newWidgetProber(aws.Config) probe.ResourceProber {
+	return widgetProber{}
+}
+
+func TestBuilder(t *testing.T) {
+	b := New("test").WithProber("aws_example_widget", []string{"AWS::Example::Widget"}, newWidgetProber)
+
+	t.Run("catalog includes built-in and added probers", func(t *testing.T) {
+		catalog := b.catalog()
+		for _, typ := range []string{"aws_dynamodb_table", "aws_s3_bucket", "aws_example_widget"} {
+			if _, ok := catalog.Factory(typ); !ok {
+				t.Errorf("expected %s to be registered", typ)
+			}
+		}
+		if got := catalog.Normalize("AWS::Example::Widget"); got != "aws_example_widget" {
+			t.Errorf("Normalize(AWS::Example::Widget) = %q, want aws_example_widget", got)
+		}
+	})
+
+	t.Run("default catalog is unchanged", func(t *testing.T) {
+		if _, ok := probe.DefaultCatalog().Factory("aws_example_widget"); ok {
+			t.Error("expected aws_example_widget to be absent from the default catalog")
+		}
+	})
+
+	t.Run("builds a probe provider", func(t *testing.T) {
+		p := b.Build()()
+
+		var resp provider.MetadataResponse
+		p.Metadata(context.Background(), provider.MetadataRequest{}, &resp)
+		if resp.TypeName != "probe" || resp.Version != "test" {
+			t.Errorf("unexpected metadata: %+v", resp)
+		}
+	})
+}
@@ -0,0 +1,190 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package probe
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Catalog maps resource type names to prober factories. Each type has a
+// canonical Terraform-style name (e.g., aws_dynamodb_table) and any number of
+// aliases, such as the CloudFormation type name (AWS::DynamoDB::Table).
+type Catalog struct {
+	mu        sync.RWMutex
+	factories map[string]ProberFactory
+	aliases   map[string][]string
+	types     map[string]string
+}
+
+// NewCatalog creates an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		factories: make(map[string]ProberFactory),
+		aliases:   make(map[string][]string),
+		types:     make(map[string]string),
+	}
+}
+
+// Register adds a resource type to the catalog. Registering a canonical type
+// again replaces its factory and aliases.
+func (c *Catalog) Register(canonicalType string, aliases []string, factory ProberFactory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, alias := range c.aliases[canonicalType] {
+		delete(c.types, alias)
+	}
+
+	c.factories[canonicalType] = factory
+	c.aliases[canonicalType] = append([]string(nil), aliases...)
+	c.types[canonicalType] = canonicalType
+	for _, alias := range aliases {
+		c.types[alias] = canonicalType
+	}
+}
+
+// Normalize converts any recognized type format to the canonical
+// Terraform-style name. Unregistered CloudFormation-style names are converted
+// by convention (AWS::Service::Resource becomes aws_service_resource); any
+// other unknown name is returned as-is.
+func (c *Catalog) Normalize(typeName string) string {
+	c.mu.RLock()
+	canonical, ok := c.types[typeName]
+	c.mu.RUnlock()
+
+	// Check direct mapping
+	if ok {
+		return canonical
+	}
+
+	// If it's already in aws_* format, return as-is (might be unsupported)
+	if strings.HasPrefix(typeName, "aws_") {
+		return typeName
+	}
+
+	// If it's in AWS::Service::Resource format, try to convert
+	if strings.HasPrefix(typeName, "AWS::") {
+		parts := strings.Split(typeName, "::")
+		if len(parts) == 3 {
+			// Convert AWS::Service::Resource to aws_service_resource (lowercase)
+			service := strings.ToLower(parts[1])
+			resource := strings.ToLower(parts[2])
+			return "aws_" + service + "_" + resource
+		}
+	}
+
+	// Return as-is for unknown types
+	return typeName
+}
+
+// Suggest returns the registered name, canonical or alias, closest to an
+// unrecognized type name, for "did you mean" messages. It returns "" if no
+// name is close enough to be a likely typo.
+func (c *Catalog) Suggest(typeName string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	want := strings.ToLower(typeName)
+	best, bestDistance := "", -1
+	for name := range c.types {
+		distance := editDistance(want, strings.ToLower(name))
+		if bestDistance < 0 || distance < bestDistance || (distance == bestDistance && name < best) {
+			best, bestDistance = name, distance
+		}
+	}
+
+	// Allow a few edits, but not so many that short names match anything
+	if bestDistance < 0 || bestDistance > 3 || bestDistance*4 > len(want) {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// Factory returns the factory registered for a canonical type.
+func (c *Catalog) Factory(canonicalType string) (ProberFactory, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	factory, ok := c.factories[canonicalType]
+	return factory, ok
+}
+
+// Types returns the registered canonical types in sorted order.
+func (c *Catalog) Types() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	types := make([]string, 0, len(c.factories))
+	for canonical := range c.factories {
+		types = append(types, canonical)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// Aliases returns the aliases registered for a canonical type.
+func (c *Catalog) Aliases(canonicalType string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]string(nil), c.aliases[canonicalType]...)
+}
+
+// Clone returns a copy of the catalog that can be extended without
+// affecting the original.
+func (c *Catalog) Clone() *Catalog {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	clone := NewCatalog()
+	for canonical, factory := range c.factories {
+		clone.factories[canonical] = factory
+		clone.aliases[canonical] = append([]string(nil), c.aliases[canonical]...)
+	}
+	for name, canonical := range c.types {
+		clone.types[name] = canonical
+	}
+
+	return clone
+}
+
+// defaultCatalog holds the types registered with the package-level Register.
+var defaultCatalog = NewCatalog()
+
+// DefaultCatalog returns the catalog used by Register and NewProberRegistry.
+// The built-in probers register themselves here when probe/awsprobe is
+// imported.
+func DefaultCatalog() *Catalog {
+	return defaultCatalog
+}
+
+// Register adds a resource type to the default catalog. It is intended to be
+// called from init functions of packages that provide probers.
+func Register(canonicalType string, aliases []string, factory ProberFactory) {
+	defaultCatalog.Register(canonicalType, aliases, factory)
+}
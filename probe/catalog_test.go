@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package probe
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// fakeProber reports every identifier as existing under a fixed type.
+type fakeProber struct {
+	terraformType string
+}
+
+func (p *fakeProber) Probe(_ context.Context, identifier string) (*ProbeResult, error) {
+	return &ProbeResult{Exists: true, TerraformType: p.terraformType, ImportID: identifier}, nil
+}
+
+func fakeFactory(terraformType string) ProberFactory {
+	return func(aws.Config) ResourceProber {
+		return &fakeProber{terraformType: terraformType}
+	}
+}
+
+func TestCatalog_Normalize(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Register("aws_example_widget", []string{"AWS::Example::Widget", "widget"}, fakeFactory("aws_example_widget"))
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"canonical", "aws_example_widget", "aws_example_widget"},
+		{"cloudformation alias", "AWS::Example::Widget", "aws_example_widget"},
+		{"short alias", "widget", "aws_example_widget"},
+		{"unknown aws type passes through", "aws_unknown_resource", "aws_unknown_resource"},
+		{"unknown cloudformation type converts", "AWS::Lambda::Function", "aws_lambda_function"},
+		{"malformed cloudformation type", "AWS::Service", "AWS::Service"},
+		{"unknown format", "some_random_type", "some_random_type"},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := catalog.Normalize(tt.input); got != tt.expected {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCatalog_Suggest(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Register("aws_example_widget", []string{"AWS::Example::Widget", "widget"}, fakeFactory("aws_example_widget"))
+	catalog.Register("aws_example_bolt", nil, fakeFactory("aws_example_bolt"))
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"aws_example_widgt", "aws_example_widget"},
+		{"aws_exmaple_bolt", "aws_example_bolt"},
+		{"AWS::Example::Widgets", "AWS::Example::Widget"},
+		{"aws_example_widget_x", "aws_example_widget"},
+		{"AWS::EXAMPLE::WIDGET", "AWS::Example::Widget"},
+		{"wdget", "widget"},
+		{"gadget", ""},
+		{"aws_lambda_function", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := catalog.Suggest(tt.input); got != tt.expected {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCatalog_Register(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Register("aws_example_widget", []string{"widget"}, fakeFactory("first"))
+
+	t.Run("replaces factory and aliases", func(t *testing.T) {
+		catalog.Register("aws_example_widget", []string{"gadget"}, fakeFactory("second"))
+
+		factory, ok := catalog.Factory("aws_example_widget")
+		if !ok {
+			t.Fatal("expected factory to be registered")
+		}
+		result, _ := factory(aws.Config{}).Probe(context.Background(), "id")
+		if result.TerraformType != "second" {
+			t.Errorf("expected replacement factory, got %q", result.TerraformType)
+		}
+
+		if got := catalog.Normalize("widget"); got != "widget" {
+			t.Errorf("expected old alias to be removed, Normalize returned %q", got)
+		}
+		if got := catalog.Normalize("gadget"); got != "aws_example_widget" {
+			t.Errorf("expected new alias to resolve, Normalize returned %q", got)
+		}
+		if got := catalog.Aliases("aws_example_widget"); !reflect.DeepEqual(got, []string{"gadget"}) {
+			t.Errorf("Aliases() = %v, want [gadget]", got)
+		}
+	})
+
+	t.Run("types are sorted", func(t *testing.T) {
+		catalog.Register("aws_example_bolt", nil, fakeFactory("aws_example_bolt"))
+
+		want := []string{"aws_example_bolt", "aws_example_widget"}
+		if got := catalog.Types(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Types() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestCatalog_Clone(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Register("aws_example_widget", []string{"widget"}, fakeFactory("aws_example_widget"))
+
+	clone := catalog.Clone()
+	clone.Register("aws_example_bolt", []string{"bolt"}, fakeFactory("aws_example_bolt"))
+
+	if _, ok := clone.Factory("aws_example_widget"); !ok {
+		t.Error("expected clone to contain the original types")
+	}
+	if _, ok := catalog.Factory("aws_example_bolt"); ok {
+		t.Error("expected registering with the clone to leave the original unchanged")
+	}
+	if got := catalog.Normalize("bolt"); got != "bolt" {
+		t.Errorf("expected clone alias to be absent from the original, Normalize returned %q", got)
+	}
+}
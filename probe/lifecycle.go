@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package probe
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Lifecycle states describe where a resource is in its life in the same
+// terms for every service, whatever status names the service uses.
+const (
+	// LifecycleActive means the resource is usable.
+	LifecycleActive = "active"
+
+	// LifecycleCreating means the resource is still being created.
+	LifecycleCreating = "creating"
+
+	// LifecycleUpdating means a change to the resource is in progress.
+	LifecycleUpdating = "updating"
+
+	// LifecycleDeleting means the resource is being deleted.
+	LifecycleDeleting = "deleting"
+
+	// LifecyclePendingDeletion means the resource is scheduled for deletion
+	// but can still be restored, like a KMS key in its waiting period.
+	LifecyclePendingDeletion = "pending_deletion"
+
+	// LifecycleArchived means the resource has been archived and must be
+	// restored before it can be used.
+	LifecycleArchived = "archived"
+)
+
+// LifecycleStates returns every lifecycle state, from the start of a
+// resource's life to its end.
+func LifecycleStates() []string {
+	return []string{
+		LifecycleCreating,
+		LifecycleActive,
+		LifecycleUpdating,
+		LifecycleDeleting,
+		LifecyclePendingDeletion,
+		LifecycleArchived,
+	}
+}
+
+// LifecycleFromStatus maps a service status to a lifecycle state using the
+// status names AWS services commonly share, in any case and in either
+// SNAKE_CASE or CamelCase (e.g., DELETING, PendingDeletion,
+// DELETE_IN_PROGRESS). Other statuses, and an empty one, are active.
+func LifecycleFromStatus(status string) string {
+	switch normalized := normalizeStatus(status); {
+	case normalized == "PENDING_DELETION", normalized == "PENDING_REPLICA_DELETION",
+		normalized == "SCHEDULED_FOR_DELETION", normalized == "DELETION_SCHEDULED":
+		return LifecyclePendingDeletion
+	case normalized == "DELETING", normalized == "TERMINATING", normalized == "SHUTTING_DOWN",
+		normalized == "DELETE_IN_PROGRESS":
+		return LifecycleDeleting
+	case normalized == "ARCHIVING", normalized == "ARCHIVED":
+		return LifecycleArchived
+	case normalized == "CREATING", normalized == "PENDING", normalized == "PROVISIONING",
+		normalized == "PENDING_IMPORT", normalized == "CREATE_IN_PROGRESS":
+		return LifecycleCreating
+	case normalized == "UPDATING", normalized == "MODIFYING",
+		strings.HasPrefix(normalized, "UPDATE_") && strings.HasSuffix(normalized, "_IN_PROGRESS"):
+		return LifecycleUpdating
+	default:
+		return LifecycleActive
+	}
+}
+
+// normalizeStatus converts a status to upper SNAKE_CASE.
+func normalizeStatus(status string) string {
+	var b strings.Builder
+	var prev rune
+	for i, r := range status {
+		switch {
+		case r == '-' || r == ' ':
+			r = '_'
+		case i > 0 && unicode.IsUpper(r) && unicode.IsLower(prev):
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		prev = r
+	}
+	return b.String()
+}
+
+// Lifecycle returns the result's lifecycle state: LifecycleState if the
+// prober set it, otherwise the state LifecycleFromStatus maps Status to. It
+// is empty for a resource that doesn't exist.
+func (r *ProbeResult) Lifecycle() string {
+	if !r.Exists {
+		return ""
+	}
+	if r.LifecycleState != "" {
+		return r.LifecycleState
+	}
+	return LifecycleFromStatus(r.Status)
+}
+
+// ExistsIn returns r if the resource doesn't exist or is in one of states,
+// and otherwise a result reporting it missing that keeps only its status.
+// Nil states accept every state.
+func (r *ProbeResult) ExistsIn(states []string) *ProbeResult {
+	if !r.Exists || states == nil || slices.Contains(states, r.Lifecycle()) {
+		return r
+	}
+	return &ProbeResult{Exists: false, Status: r.Status}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package probe
+
+import "testing"
+
+func TestLifecycleFromStatus(t *testing.T) {
+	tests := map[string]string{
+		"":                                    LifecycleActive,
+		"ACTIVE":                              LifecycleActive,
+		"Enabled":                             LifecycleActive,
+		"INACCESSIBLE_ENCRYPTION_CREDENTIALS": LifecycleActive,
+		"CREATING":                            LifecycleCreating,
+		"CREATE_IN_PROGRESS":                  LifecycleCreating,
+		"PendingImport":                       LifecycleCreating,
+		"UPDATING":                            LifecycleUpdating,
+		"modifying":                           LifecycleUpdating,
+		"UPDATE_ROLLBACK_IN_PROGRESS":         LifecycleUpdating,
+		"UPDATE_COMPLETE":                     LifecycleActive,
+		"DELETING":                            LifecycleDeleting,
+		"shutting-down":                       LifecycleDeleting,
+		"DELETE_IN_PROGRESS":                  LifecycleDeleting,
+		"PendingDeletion":                     LifecyclePendingDeletion,
+		"PendingReplicaDeletion":              LifecyclePendingDeletion,
+		"ARCHIVING":                           LifecycleArchived,
+		"ARCHIVED":                            LifecycleArchived,
+	}
+	for status, expected := range tests {
+		if got := LifecycleFromStatus(status); got != expected {
+			t.Errorf("LifecycleFromStatus(%q) = %q, want %q", status, got, expected)
+		}
+	}
+}
+
+func TestProbeResult_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   ProbeResult
+		expected string
+	}{
+		{name: "missing", result: ProbeResult{Exists: false, Status: "DELETING"}, expected: ""},
+		{name: "from status", result: ProbeResult{Exists: true, Status: "DELETING"}, expected: LifecycleDeleting},
+		{name: "no status", result: ProbeResult{Exists: true}, expected: LifecycleActive},
+		{
+			name:     "set by the prober",
+			result:   ProbeResult{Exists: true, Status: "Disabled", LifecycleState: LifecyclePendingDeletion},
+			expected: LifecyclePendingDeletion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Lifecycle(); got != tt.expected {
+				t.Errorf("Lifecycle() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProbeResult_ExistsIn(t *testing.T) {
+	deleting := &ProbeResult{Exists: true, Arn: "arn:aws:dynamodb:us-east-1:123456789012:table/orders", Status: "DELETING"}
+	missing := &ProbeResult{Exists: false}
+
+	if got := deleting.ExistsIn(nil); got != deleting {
+		t.Error("expected nil states to accept every state")
+	}
+	if got := deleting.ExistsIn([]string{LifecycleActive, LifecycleDeleting}); got != deleting {
+		t.Error("expected a listed state to be accepted")
+	}
+	if got := missing.ExistsIn([]string{LifecycleActive}); got != missing {
+		t.Error("expected a missing resource to stay missing")
+	}
+
+	got := deleting.ExistsIn([]string{LifecycleActive})
+	if got.Exists || got.Arn != "" || got.Status != "DELETING" {
+		t.Errorf("expected a missing result keeping the status, got %+v", got)
+	}
+}
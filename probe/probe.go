@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package probe defines the interface for probing AWS resources and the
+// registry that maps resource type names to probers. Tools can embed the
+// registry to run probes directly, and register additional resource types
+// with Register. Import probe/awsprobe for the built-in AWS probers.
+package probe
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ProbeResult contains the results of probing an AWS resource.
+type ProbeResult struct {
+	// Exists indicates whether the resource was found.
+	Exists bool
+
+	// Arn is the Amazon Resource Name, if available.
+	Arn string
+
+	// Properties contains the resource properties as a map.
+	// The structure varies by resource type.
+	Properties map[string]any
+
+	// Tags contains the resource tags, if available.
+	Tags map[string]string
+
+	// Status is the resource's status as the service reports it (e.g.,
+	// ACTIVE for a DynamoDB table), for resources that have one.
+	Status string
+
+	// LifecycleState is Status mapped to one of the Lifecycle states (e.g.,
+	// LifecycleDeleting for a DynamoDB table in DELETING). Probers that
+	// leave it empty get the state LifecycleFromStatus maps Status to; see
+	// Lifecycle.
+	LifecycleState string
+
+	// TerraformType is the hashicorp/aws resource type that manages the
+	// resource (e.g., aws_dynamodb_table).
+	TerraformType string
+
+	// ImportID is the identifier `terraform import` expects for TerraformType.
+	ImportID string
+}
+
+// ResourceProber defines the interface for probing AWS resources.
+// Each supported resource type implements this interface. The probetest
+// package checks an implementation against this contract.
+type ResourceProber interface {
+	// Probe checks whether a resource exists and retrieves its properties.
+	// The identifier format depends on the resource type (e.g., table name for DynamoDB).
+	// Returns a ProbeResult with Exists=false if the resource is not found.
+	// Returns an error only for unexpected failures (not for "resource not found").
+	Probe(ctx context.Context, identifier string) (*ProbeResult, error)
+}
+
+// ServiceProber is implemented by probers that can name the AWS service they
+// call, by its endpoint prefix (e.g., "dynamodb", "s3"). The provider uses it
+// to check that the service is running before probing an emulator.
+type ServiceProber interface {
+	ResourceProber
+
+	// Service returns the endpoint prefix of the service Probe calls.
+	Service() string
+}
+
+// ImmutableProperty is a property that can't change once a resource is
+// created, so a different value means the resource can't be adopted as is.
+type ImmutableProperty struct {
+	// Name is the key of the property in ProbeResult.Properties.
+	Name string
+
+	// Unordered marks list properties whose element order doesn't matter.
+	Unordered bool
+}
+
+// ImmutableProber is implemented by probers that know which of their
+// resource type's properties are immutable. The provider compares them with
+// the properties a configuration intends before it adopts a resource.
+type ImmutableProber interface {
+	ResourceProber
+
+	// ImmutableProperties returns the immutable properties Probe reports.
+	ImmutableProperties() []ImmutableProperty
+}
+
+// SensitiveProber is implemented by probers whose results include
+// properties that shouldn't appear in plan output, such as policies or
+// connection endpoints. The provider reports them separately, marked
+// sensitive, or leaves them out.
+type SensitiveProber interface {
+	ResourceProber
+
+	// SensitiveProperties returns the keys of the sensitive properties
+	// Probe reports.
+	SensitiveProperties() []string
+}
+
+// IdentifierValidator is implemented by probers that know the syntax of
+// their resource type's identifiers. The provider checks identifiers with it
+// at plan time, so a malformed one is reported before any AWS call.
+type IdentifierValidator interface {
+	ResourceProber
+
+	// ValidateIdentifier returns an error describing what is wrong with
+	// identifier, or nil if it could name a resource.
+	ValidateIdentifier(identifier string) error
+}
+
+// IdentifierLinter is implemented by probers whose resource types have
+// naming rules that older resources may predate. The provider warns about
+// identifiers that break them but probes anyway.
+type IdentifierLinter interface {
+	ResourceProber
+
+	// LintIdentifier returns an error describing which current naming rule
+	// identifier breaks, or nil if it follows them all.
+	LintIdentifier(identifier string) error
+}
+
+// ProberFactory is a function that creates a ResourceProber from an AWS config.
+type ProberFactory func(cfg aws.Config) ResourceProber
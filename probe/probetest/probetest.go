@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package probetest is a conformance suite for probe.ResourceProber
+// implementations. Run drives a prober against a scripted backend and fails
+// the test if the prober violates the ResourceProber contract: a missing
+// resource is reported with Exists=false and a nil error, an existing one
+// has an ARN, tags and properties, and throttling or server errors surface
+// as errors instead of being mistaken for a missing resource.
+package probetest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/shakefu/terraform-provider-probe/probe"
+)
+
+// Backend is a scripted AWS endpoint serving one resource type.
+type Backend interface {
+	// Config returns an AWS config that sends the prober's requests to the
+	// backend. Retries should not back off, so error cases run quickly.
+	Config() aws.Config
+
+	// Create makes a resource with the given identifier and tags exist.
+	Create(t *testing.T, identifier string, tags map[string]string)
+
+	// Fail makes every subsequent request fail with the given HTTP status
+	// and AWS error code.
+	Fail(t *testing.T, status int, code string)
+}
+
+// Options customizes a conformance run.
+type Options struct {
+	// ExistingID and MissingID are the identifiers used for the existing
+	// and missing resource. They default to names that are valid for most
+	// resource types.
+	ExistingID string
+	MissingID  string
+
+	// CheckProperties, if set, is called with the properties of an existing
+	// resource, e.g. to verify they convert to Terraform values. A panic is
+	// reported as a failure.
+	CheckProperties func(properties map[string]any) error
+}
+
+// ConformanceTag is the tag Run applies to the existing resource and
+// expects the prober to report.
+const ConformanceTag = "probetest"
+
+// errorCases are the failures a prober must surface as errors.
+var errorCases = []struct {
+	name   string
+	status int
+	code   string
+}{
+	{"throttling", http.StatusBadRequest, "ThrottlingException"},
+	{"too many requests", http.StatusTooManyRequests, "TooManyRequestsException"},
+	{"internal error", http.StatusInternalServerError, "InternalError"},
+	{"service unavailable", http.StatusServiceUnavailable, "ServiceUnavailable"},
+}
+
+// Run checks the prober built by factory against backends created by
+// newBackend. Each subtest gets a fresh backend.
+func Run(t *testing.T, factory probe.ProberFactory, newBackend func(t *testing.T) Backend, opts Options) {
+	t.Helper()
+
+	if opts.ExistingID == "" {
+		opts.ExistingID = "probetest-existing"
+	}
+	if opts.MissingID == "" {
+		opts.MissingID = "probetest-missing"
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		backend := newBackend(t)
+		prober := factory(backend.Config())
+
+		result, err := prober.Probe(context.Background(), opts.MissingID)
+		if err != nil {
+			t.Fatalf("missing resource must not return an error, got: %v", err)
+		}
+		if result == nil {
+			t.Fatal("missing resource must return a non-nil result")
+		}
+		if result.Exists {
+			t.Error("missing resource must report Exists=false")
+		}
+		if result.Arn != "" {
+			t.Errorf("missing resource must not report an ARN, got %q", result.Arn)
+		}
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		backend := newBackend(t)
+		backend.Create(t, opts.ExistingID, map[string]string{ConformanceTag: "conformance"})
+		prober := factory(backend.Config())
+
+		result, err := prober.Probe(context.Background(), opts.ExistingID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result == nil || !result.Exists {
+			t.Fatal("existing resource must report Exists=true")
+		}
+		if !strings.HasPrefix(result.Arn, "arn:") {
+			t.Errorf("existing resource must report an ARN, got %q", result.Arn)
+		}
+		if result.Tags[ConformanceTag] != "conformance" {
+			t.Errorf("existing resource must report its tags, got %v", result.Tags)
+		}
+		if len(result.Properties) == 0 {
+			t.Error("existing resource must report properties")
+		}
+		if result.TerraformType != "" && result.ImportID == "" {
+			t.Errorf("TerraformType %s is set without an ImportID", result.TerraformType)
+		}
+
+		if opts.CheckProperties != nil {
+			if err := checkProperties(opts.CheckProperties, result.Properties); err != nil {
+				t.Errorf("properties failed the check: %v", err)
+			}
+		}
+	})
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend := newBackend(t)
+			backend.Create(t, opts.ExistingID, nil)
+			backend.Fail(t, tc.status, tc.code)
+			prober := factory(backend.Config())
+
+			result, err := prober.Probe(context.Background(), opts.ExistingID)
+			if err == nil {
+				t.Fatalf("%d %s must surface as an error, got result %+v", tc.status, tc.code, result)
+			}
+		})
+	}
+}
+
+// checkProperties calls check, converting a panic into an error.
+func checkProperties(check func(map[string]any) error, properties map[string]any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return check(properties)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package probe
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ProberRegistry manages ResourceProber instances for different resource types.
+// It is safe for concurrent use, so data sources can share one.
+type ProberRegistry struct {
+	cfg     aws.Config
+	catalog *Catalog
+
+	mu      sync.Mutex
+	probers map[string]ResourceProber
+}
+
+// NewProberRegistry creates a new ProberRegistry with the given AWS config,
+// backed by the default catalog.
+func NewProberRegistry(cfg aws.Config) *ProberRegistry {
+	return NewProberRegistryWithCatalog(cfg, defaultCatalog)
+}
+
+// NewProberRegistryWithCatalog creates a new ProberRegistry backed by catalog.
+func NewProberRegistryWithCatalog(cfg aws.Config, catalog *Catalog) *ProberRegistry {
+	return &ProberRegistry{
+		cfg:     cfg,
+		catalog: catalog,
+		probers: make(map[string]ResourceProber),
+	}
+}
+
+// GetProber returns a ResourceProber for the given resource type.
+// The type can be either Terraform-style (aws_dynamodb_table) or
+// CloudFormation-style (AWS::DynamoDB::Table).
+// Returns an error if the type is not supported.
+func (r *ProberRegistry) GetProber(resourceType string) (ResourceProber, error) {
+	// Normalize the type name
+	canonicalType := r.catalog.Normalize(resourceType)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Check if we already have an instance
+	if prober, ok := r.probers[canonicalType]; ok {
+		return prober, nil
+	}
+
+	// Get the factory for this type
+	factory, ok := r.catalog.Factory(canonicalType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported resource type: %s", resourceType)
+	}
+
+	// Create and cache the prober
+	prober := factory(r.cfg)
+	r.probers[canonicalType] = prober
+
+	return prober, nil
+}
+
+// SupportedTypes returns a list of all supported resource types.
+func (r *ProberRegistry) SupportedTypes() []string {
+	return r.catalog.Types()
+}
+
+// Catalog returns the catalog backing the registry.
+func (r *ProberRegistry) Catalog() *Catalog {
+	return r.catalog
+}
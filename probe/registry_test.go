@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package probe
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestProberRegistry_GetProber(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Register("aws_example_widget", []string{"AWS::Example::Widget"}, fakeFactory("aws_example_widget"))
+	registry := NewProberRegistryWithCatalog(aws.Config{Region: "us-east-1"}, catalog)
+
+	t.Run("returns prober for registered type", func(t *testing.T) {
+		prober, err := registry.GetProber("aws_example_widget")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, err := prober.Probe(context.Background(), "my-widget")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Exists || result.ImportID != "my-widget" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("aliases return the same instance", func(t *testing.T) {
+		prober1, _ := registry.GetProber("aws_example_widget")
+		prober2, err := registry.GetProber("AWS::Example::Widget")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if prober1 != prober2 {
+			t.Error("expected same prober for equivalent type names")
+		}
+	})
+
+	t.Run("returns error for unregistered type", func(t *testing.T) {
+		if _, err := registry.GetProber("aws_unsupported_resource"); err == nil {
+			t.Fatal("expected error for unregistered type")
+		}
+	})
+}
+
+func TestProberRegistry_GetProberConcurrent(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Register("aws_example_widget", []string{"AWS::Example::Widget"}, fakeFactory("aws_example_widget"))
+	registry := NewProberRegistryWithCatalog(aws.Config{Region: "us-east-1"}, catalog)
+
+	probers := make([]ResourceProber, 16)
+	var wg sync.WaitGroup
+	for i := range probers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := "aws_example_widget"
+			if i%2 == 1 {
+				name = "AWS::Example::Widget"
+			}
+			probers[i], _ = registry.GetProber(name)
+		}()
+	}
+	wg.Wait()
+
+	for i, prober := range probers {
+		if prober == nil || prober != probers[0] {
+			t.Fatalf("prober %d = %v, want the shared instance %v", i, prober, probers[0])
+		}
+	}
+}
+
+func TestProberRegistry_SupportedTypes(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Register("aws_example_widget", []string{"widget"}, fakeFactory("aws_example_widget"))
+	registry := NewProberRegistryWithCatalog(aws.Config{}, catalog)
+
+	types := registry.SupportedTypes()
+	if len(types) != 1 || types[0] != "aws_example_widget" {
+		t.Errorf("SupportedTypes() = %v, want [aws_example_widget]", types)
+	}
+	if registry.Catalog() != catalog {
+		t.Error("expected Catalog() to return the backing catalog")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("aws_example_registered", []string{"example_registered"}, fakeFactory("aws_example_registered"))
+
+	registry := NewProberRegistry(aws.Config{})
+	if _, err := registry.GetProber("example_registered"); err != nil {
+		t.Fatalf("expected Register to add the type to the default catalog: %v", err)
+	}
+}